@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"termtyper/words"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+type BookSelectHandler struct {
+	*BaseStateHandler
+	books  []string
+	cursor int
+	err    error
+	menu   MainMenuHandler
+}
+
+func NewBookSelectHandler(menu MainMenuHandler) *BookSelectHandler {
+	books, err := words.ListBooks(booksDir)
+	return &BookSelectHandler{
+		BaseStateHandler: NewBaseStateHandler(StateBookSelect),
+		books:            books,
+		err:              err,
+		menu:             menu,
+	}
+}
+
+func (h *BookSelectHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+q":
+			if h.ValidateTransition(StateMainMenu, context) {
+				return NewMainMenuHandler(context.model.session.User, context.model), nil
+			}
+
+		case "enter":
+			if len(h.books) == 0 {
+				return h, nil
+			}
+			book, err := words.LoadBook(filepath.Join(booksDir, h.books[h.cursor]))
+			if err != nil {
+				h.err = err
+				return h, nil
+			}
+			if h.ValidateTransition(StateBookTest, context) {
+				return NewBookTestHandler(h.menu, context.model, book), nil
+			}
+
+		case "up", "k":
+			if h.cursor == 0 {
+				h.cursor = max(len(h.books)-1, 0)
+			} else {
+				h.cursor--
+			}
+
+		case "down", "j":
+			if h.cursor >= len(h.books)-1 {
+				h.cursor = 0
+			} else {
+				h.cursor++
+			}
+		}
+	}
+	return h, nil
+}
+
+func (h *BookSelectHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+
+	title := style("Books", m.styles.themeFunc)
+	title = lipgloss.NewStyle().PaddingBottom(1).Render(title)
+
+	var items []string
+	itemStyle := lipgloss.NewStyle().PaddingTop(1)
+	for i, book := range h.books {
+		choiceShow := style(book, m.styles.toEnter)
+		choiceShow = wrapWithCursor(h.cursor == i, choiceShow, m.styles.toEnter)
+		items = append(items, itemStyle.Render(choiceShow))
+	}
+	if len(h.books) == 0 {
+		items = append(items, style(fmt.Sprintf("No .txt or .md books in %s", booksDir), m.styles.toEnter))
+	}
+	if h.err != nil {
+		items = append(items, itemStyle.Render(style(h.err.Error(), m.styles.mistake)))
+	}
+
+	helpText := lipgloss.NewStyle().Faint(true).Render("\nenter: start or resume, esc/ctrl+q: back")
+
+	joined := lipgloss.JoinVertical(lipgloss.Left, append([]string{title}, items...)...)
+	joined = lipgloss.JoinVertical(lipgloss.Left, joined, helpText)
+	s := lipgloss.NewStyle().Align(lipgloss.Left).Render(joined)
+
+	return lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, s)
+}
+
+func (h *BookSelectHandler) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
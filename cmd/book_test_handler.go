@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"strconv"
+
+	"termtyper/database"
+	"termtyper/words"
+)
+
+type BookTestHandler struct {
+	*stopwatchTest
+	book    words.Book
+	passage int
+}
+
+// NewBookTestHandler resumes a book at the passage after the last one the
+// user finished, starting over once the whole book has been typed.
+func NewBookTestHandler(menu MainMenuHandler, m *model, book words.Book) *BookTestHandler {
+	passage := loadBookProgress(m, book.Name)
+	if passage < 0 || passage >= len(book.Passages) {
+		passage = 0
+	}
+
+	h := &BookTestHandler{book: book, passage: passage}
+	h.stopwatchTest = newStopwatchTest(StateBookTest, menu, []rune(book.Passages[passage]), h)
+	return h
+}
+
+func (h *BookTestHandler) testType() string {
+	return "book"
+}
+
+func (h *BookTestHandler) testValue() int {
+	return h.passage
+}
+
+func (h *BookTestHandler) label() string {
+	return h.progress()
+}
+
+func (h *BookTestHandler) restart(context *StateContext) StateHandler {
+	return NewBookTestHandler(h.base.mainMenu, context.model, h.book)
+}
+
+// finish moves the bookmark on past a passage that was passed and saved.
+func (h *BookTestHandler) finish(context *StateContext, testID int64, results *ResultsHandler) {
+	if h.base.failed == "" && !h.base.integrity.void {
+		saveBookProgress(context, h.book.Name, h.passage+1)
+	}
+
+	book := h.book
+	results.book = &book
+	results.bookProgress = h.progress()
+}
+
+func (h BookTestHandler) progress() string {
+	return h.book.Name + " " + strconv.Itoa(h.passage+1) + "/" + strconv.Itoa(len(h.book.Passages))
+}
+
+// loadBookProgress reads the saved passage for logged-in users and the
+// session's bookmark for guests.
+func loadBookProgress(m *model, book string) int {
+	if m.session.User.Id > 0 {
+		passage, err := database.GetBookProgress(m.context.UserRepository, m.session.User.Id, book)
+		if err == nil {
+			return passage
+		}
+	}
+
+	return m.session.BookProgress[book]
+}
+
+func saveBookProgress(context *StateContext, book string, passage int) {
+	session := context.model.session
+	if session.User.Id > 0 {
+		_ = database.SaveBookProgress(context.model.context.UserRepository, session.User.Id, book, passage)
+		return
+	}
+
+	if session.BookProgress == nil {
+		session.BookProgress = make(map[string]int)
+	}
+	session.BookProgress[book] = passage
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/database"
+	"termtyper/words"
+)
+
+func TestBookResumesForGuests(t *testing.T) {
+	m := &model{session: &Session{User: &database.ApplicationUser{Id: -1, Config: &database.DefaultConfig}}}
+	context := &StateContext{model: m}
+	menu := MainMenuHandler{currentUser: m.session.User}
+	book := words.Book{Name: "short.txt", Passages: []string{"one.", "two.", "three."}}
+
+	h := NewBookTestHandler(menu, m, book)
+	if h.passage != 0 || string(h.base.wordsToEnter) != "one." {
+		t.Fatalf("a new book should start at the first passage, got %d", h.passage)
+	}
+
+	underSessionLock(t, m, func() {
+		saveBookProgress(context, book.Name, 2)
+		h = NewBookTestHandler(menu, m, book)
+	})
+	if h.passage != 2 || string(h.base.wordsToEnter) != "three." {
+		t.Errorf("expected to resume at passage 2, got %d", h.passage)
+	}
+	if h.progress() != "short.txt 3/3" {
+		t.Errorf("unexpected progress %q", h.progress())
+	}
+
+	saveBookProgress(context, book.Name, 3)
+	if h = NewBookTestHandler(menu, m, book); h.passage != 0 {
+		t.Errorf("a finished book should start over, got passage %d", h.passage)
+	}
+}
+
+func TestBookKeepsPlaceAfterFailedPassage(t *testing.T) {
+	m := newGuestModel()
+	context := &StateContext{model: m}
+	menu := MainMenuHandler{currentUser: m.session.User}
+	book := words.Book{Name: "failed.txt", Passages: []string{"one.", "two."}}
+
+	h := NewBookTestHandler(menu, m, book)
+	typeText(&h.base, "on")
+	h.base.failed = "wrong key"
+	h.calculateResults(m, context)
+	if h = NewBookTestHandler(menu, m, book); h.passage != 0 {
+		t.Errorf("a failed passage should be taken again, got passage %d", h.passage)
+	}
+
+	typeText(&h.base, "one.")
+	h.calculateResults(m, context)
+	if h = NewBookTestHandler(menu, m, book); h.passage != 1 {
+		t.Errorf("a finished passage should move the bookmark on, got passage %d", h.passage)
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import "unicode"
+
+// characterStats accounts for the characters of a test the way monkeytype
+// does. Letters typed right, typed wrong, typed where the word had already
+// ended, and skipped by typing the separator early are counted separately;
+// the separators between words are counted on their own.
+type characterStats struct {
+	correct   int
+	incorrect int
+	extra     int
+	missed    int
+	// spaces is every separator typed where one was expected, and
+	// correctSpaces those that followed a correctly typed word.
+	spaces        int
+	correctSpaces int
+	// correctWordChars is the letters of the words typed without any error,
+	// which is all net WPM counts.
+	correctWordChars int
+}
+
+// wordCharacterStats is the accounting of one word of the text.
+type wordCharacterStats struct {
+	correct   int
+	incorrect int
+	extra     int
+	missed    int
+}
+
+func (w wordCharacterStats) clean() bool {
+	return w.incorrect == 0 && w.extra == 0 && w.missed == 0
+}
+
+// wordStats splits what has been typed so far into the words of the text and
+// accounts for each of them. Text without anything to type, as in zen mode,
+// counts everything typed as correct.
+func (base TestBase) wordStats() []wordCharacterStats {
+	var stats []wordCharacterStats
+	var word wordCharacterStats
+	inWord := false
+
+	for i, typed := range base.inputBuffer {
+		expected := typed
+		if i < len(base.wordsToEnter) {
+			expected = base.wordsToEnter[i]
+		}
+
+		if unicode.IsSpace(expected) {
+			if inWord {
+				word.extra += len(base.extra[i])
+				stats = append(stats, word)
+				word = wordCharacterStats{}
+				inWord = false
+			}
+			continue
+		}
+
+		inWord = true
+		switch {
+		case typed == expected:
+			word.correct++
+		case typed == skippedRune || unicode.IsSpace(typed):
+			word.missed++
+		default:
+			word.incorrect++
+		}
+	}
+
+	if inWord {
+		word.extra += len(base.extra[len(base.inputBuffer)])
+		stats = append(stats, word)
+	}
+
+	return stats
+}
+
+// characterStats totals the accounting of every word typed so far. A letter
+// typed over a separator, or kept in extra with word-based input, is extra for
+// the word before it.
+func (base TestBase) characterStats() characterStats {
+	var stats characterStats
+
+	words := base.wordStats()
+	for _, word := range words {
+		stats.correct += word.correct
+		stats.incorrect += word.incorrect
+		stats.missed += word.missed
+		stats.extra += word.extra
+	}
+
+	wordIndex := 0
+	inWord := false
+	for i, typed := range base.inputBuffer {
+		expected := typed
+		if i < len(base.wordsToEnter) {
+			expected = base.wordsToEnter[i]
+		}
+
+		if !unicode.IsSpace(expected) {
+			inWord = true
+			continue
+		}
+		if inWord {
+			wordIndex++
+			inWord = false
+		}
+
+		previousClean := wordIndex == 0 || words[wordIndex-1].clean()
+		if typed == expected {
+			stats.spaces++
+			if previousClean {
+				stats.correctSpaces++
+			}
+		} else {
+			stats.extra++
+			if wordIndex > 0 {
+				words[wordIndex-1].extra++
+			}
+		}
+	}
+
+	// The word being typed counts while it is right so far.
+	for _, word := range words {
+		if word.clean() {
+			stats.correctWordChars += word.correct
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import "testing"
+
+func TestCharacterStats(t *testing.T) {
+	base := newTestBase("the quick fox jumps")
+	typeText(&base, "the quxck foxxj")
+
+	chars := base.characterStats()
+	want := characterStats{
+		correct:   11,
+		incorrect: 1,
+		extra:     1,
+		missed:    0,
+		spaces:    2,
+		// Only the space after "the" follows a clean word.
+		correctSpaces: 1,
+		// "the" and the "j" of the word being typed; "quick" has a wrong
+		// letter and "fox" ran long.
+		correctWordChars: 4,
+	}
+	if chars != want {
+		t.Errorf("expected %+v, got %+v", want, chars)
+	}
+
+	if wpm := base.calculateNormalizedWpm(1); wpm != 1 {
+		t.Errorf("expected a net WPM of 1, got %v", wpm)
+	}
+	if raw := base.calculateRawWpm(1); raw != 3 {
+		t.Errorf("expected a raw WPM of 3, got %v", raw)
+	}
+}
+
+func TestCharacterStatsMissed(t *testing.T) {
+	base := newTestBase("ab cd")
+	typeText(&base, "a ")
+
+	chars := base.characterStats()
+	if chars.correct != 1 || chars.missed != 1 || chars.correctWordChars != 0 {
+		t.Errorf("expected one correct and one missed character, got %+v", chars)
+	}
+}
+
+func TestCalculateAccuracyCountsCorrectedMistakes(t *testing.T) {
+	base := newTestBase("ab")
+	typeText(&base, "x")
+	handleBackspace(&base)
+	typeText(&base, "ab")
+
+	if accuracy := base.calculateAccuracy(); accuracy < 66 || accuracy > 67 {
+		t.Errorf("expected 2 of 3 key presses to be right, got %.1f%%", accuracy)
+	}
+}
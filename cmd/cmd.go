@@ -10,6 +10,7 @@ import (
 	"sync"
 	"syscall"
 	"termtyper/database"
+	"termtyper/words"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -35,14 +36,29 @@ var (
 	host           = "localhost"
 	port           = 22222
 	privateKeyPath string
+	wordListsDir   string
+	booksDir       string
 )
 
+// Session is one connection's state. mu is held by model.Update and
+// model.View, so handlers already have it and must not take it again.
 type Session struct {
 	mu            sync.Mutex
 	User          *database.ApplicationUser
 	RemoteAddr    string
 	Authenticated bool
 	LastActivity  time.Time
+	// BigramStats holds what a guest has typed this session, since guests
+	// have no test history to draw practice text from.
+	BigramStats map[string]database.BigramStat
+	// BookProgress tracks a guest's place in each book for this session.
+	BookProgress map[string]int
+	// MissedWords are the words mistyped in the last test, for drilling
+	// them straight after.
+	MissedWords []database.MissedWord
+	// Replays holds a guest's latest attempt at each seeded text, for racing
+	// it as a ghost.
+	Replays map[string]keptAttempt
 }
 
 var (
@@ -51,6 +67,12 @@ var (
 	RootCmd       = &cobra.Command{
 		Use:  "TermTyper",
 		Long: "TermTyper - Terminal Typing Test",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := words.LoadUserSources(wordListsDir); err != nil {
+				log.Printf("Skipping invalid word lists: %v", err)
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			termWidth, termHeight, err := term.GetSize(int(os.Stdout.Fd()))
 
@@ -134,6 +156,8 @@ var (
 
 func init() {
 	RootCmd.PersistentFlags().BoolVar(&sshServerFlag, "ssh-server", false, "Serve as an SSH server")
+	RootCmd.PersistentFlags().StringVar(&wordListsDir, "wordlists", "./data/wordlists", "directory with extra word lists (.json or .txt)")
+	RootCmd.PersistentFlags().StringVar(&booksDir, "books", "./data/books", "directory with books to type through (.txt or .md)")
 	serveCmd.Flags().StringVarP(&privateKeyPath, "key", "k", "id_rsa", "path to the server key")
 	serveCmd.Flags().StringVarP(&host, "host", "", "localhost", "address to serve on (localhost or network)")
 	serveCmd.Flags().IntVarP(&port, "port", "p", port, "port to serve on")
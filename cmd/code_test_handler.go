@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"math"
+	"strings"
+
+	"termtyper/words"
+
+	"charm.land/lipgloss/v2"
+)
+
+// codeLinesAround is how many lines of a snippet are shown above and below
+// the cursor's line.
+const codeLinesAround = 4
+
+type CodeTestHandler struct {
+	*stopwatchTest
+	snippet words.CodeSnippet
+}
+
+// NewCodeTestHandler starts a test on a code snippet. Code is typed key for
+// key, so enter and tab type and word input is off.
+func NewCodeTestHandler(menu MainMenuHandler) *CodeTestHandler {
+	snippet := menu.textGenerator.GenerateSnippet(menu.currentUser.Config.CodeLanguage)
+	h := &CodeTestHandler{snippet: snippet}
+	h.stopwatchTest = newStopwatchTest(StateCodeTest, menu, []rune(snippet.Code), h)
+	h.typesLines = true
+	h.base.wordInput = false
+	return h
+}
+
+func (h *CodeTestHandler) testType() string {
+	return "code"
+}
+
+func (h *CodeTestHandler) testValue() int {
+	return h.snippet.Id
+}
+
+func (h *CodeTestHandler) label() string {
+	return h.snippet.Language
+}
+
+func (h *CodeTestHandler) restart(context *StateContext) StateHandler {
+	return NewCodeTestHandler(h.base.mainMenu)
+}
+
+func (h *CodeTestHandler) finish(context *StateContext, testID int64, results *ResultsHandler) {
+	snippet := h.snippet
+	results.snippet = &snippet
+}
+
+func (h *CodeTestHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+	s := ""
+	lines, cursorLine := h.base.renderCodeLines(m.styles)
+
+	low := int(math.Max(0, float64(cursorLine-codeLinesAround)))
+	high := int(math.Min(float64(len(lines)), float64(cursorLine+codeLinesAround+1)))
+	linesAroundCursor := strings.Join(lines[low:high], "\n")
+
+	s += positionVertically(termHeight - 2*codeLinesAround)
+	maxLineLen := 0
+	for _, line := range lines {
+		maxLineLen = int(math.Max(float64(maxLineLen), float64(lipgloss.Width(line))))
+	}
+	indentBy := uint(math.Max(0, float64(termWidth/2-maxLineLen/2)))
+
+	s += m.indent(h.header(m), indentBy) + "\n\n" + m.indent(linesAroundCursor, indentBy)
+	s += "\n\n\n"
+	s += lipgloss.PlaceHorizontal(termWidth, lipgloss.Center, style("ctrl+r to restart, ctrl+q to menu", m.styles.toEnter))
+
+	return s
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"termtyper/database"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// customSetting is the test length a CustomValueHandler edits.
+type customSetting int
+
+const (
+	customTime customSetting = iota
+	customWords
+)
+
+type CustomValueHandler struct {
+	*BaseStateHandler
+	form    *huh.Form
+	setting customSetting
+	value   *string
+}
+
+// NewCustomValueHandler edits a timer or word-count length that isn't one of
+// the presets.
+func NewCustomValueHandler(config *database.UserConfig, setting customSetting) *CustomValueHandler {
+	value := new(string)
+
+	input := huh.NewInput().Value(value)
+	switch setting {
+	case customTime:
+		*value = strconv.Itoa(config.Time)
+		input = input.
+			Title("Timer length").
+			Description("seconds, or a duration like 5m").
+			Placeholder("300")
+	case customWords:
+		*value = strconv.Itoa(config.Words)
+		input = input.
+			Title("Number of words").
+			Placeholder("200")
+	}
+	input = input.Validate(func(str string) error {
+		_, err := parseCustomValue(*config, setting, str)
+		return err
+	})
+
+	return &CustomValueHandler{
+		BaseStateHandler: NewBaseStateHandler(StateCustomValue),
+		form:             huh.NewForm(huh.NewGroup(input)),
+		setting:          setting,
+		value:            value,
+	}
+}
+
+// parseCustomValue reads a custom length and checks it against the limits of
+// UserConfig.
+func parseCustomValue(config database.UserConfig, setting customSetting, str string) (int, error) {
+	str = strings.TrimSpace(str)
+	number, err := strconv.Atoi(str)
+
+	switch setting {
+	case customTime:
+		if err != nil {
+			duration, durationErr := time.ParseDuration(str)
+			if durationErr != nil || duration%time.Second != 0 {
+				return 0, fmt.Errorf("enter whole seconds, e.g. 300 or 5m")
+			}
+			number = int(duration / time.Second)
+		}
+		config.Time = number
+		return number, database.ValidateConfigField(config, "Time")
+
+	default:
+		if err != nil {
+			return 0, fmt.Errorf("enter a number of words")
+		}
+		config.Words = number
+		return number, database.ValidateConfigField(config, "Words")
+	}
+}
+
+func (h *CustomValueHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+q":
+			if h.ValidateTransition(StateSettings, context) {
+				return NewSettingsHandler(context.model.session.User), nil
+			}
+		}
+	}
+
+	var commands []tea.Cmd
+	updatedForm, formCmd := h.form.Update(msg)
+	if f, ok := updatedForm.(*huh.Form); ok {
+		h.form = f
+		commands = append(commands, formCmd)
+	}
+
+	if h.form.State == huh.StateCompleted {
+		newUserConfig := context.model.session.User.Config
+		if value, err := parseCustomValue(*newUserConfig, h.setting, *h.value); err == nil {
+			if h.setting == customTime {
+				newUserConfig.Time = value
+			} else {
+				newUserConfig.Words = value
+			}
+
+			database.UpdateUserConfigStandalone(
+				context.model.context.UserRepository,
+				context.model.session.User.Id,
+				UserConfigToMap(newUserConfig))
+		}
+
+		if h.ValidateTransition(StateSettings, context) {
+			return NewSettingsHandler(context.model.session.User), nil
+		}
+	}
+
+	return h, tea.Batch(commands...)
+}
+
+func (h *CustomValueHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+
+	title := "Custom Timer"
+	if h.setting == customWords {
+		title = "Custom Word Count"
+	}
+	title = lipgloss.NewStyle().PaddingBottom(1).Render(style(title, m.styles.themeFunc))
+
+	helpText := lipgloss.NewStyle().Faint(true).Render("enter: save • esc/ctrl+q: back without saving")
+
+	joined := lipgloss.JoinVertical(lipgloss.Left, title, h.form.View(), "", helpText)
+	s := lipgloss.NewStyle().Align(lipgloss.Left).Render(joined)
+	centeredText := lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, s)
+
+	return centeredText
+}
+
+func (h *CustomValueHandler) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/database"
+)
+
+func TestParseCustomValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting customSetting
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "seconds", setting: customTime, input: "300", want: 300},
+		{name: "duration", setting: customTime, input: " 5m ", want: 300},
+		{name: "longest timer", setting: customTime, input: "24m", want: 1440},
+		{name: "timer too long", setting: customTime, input: "1441", wantErr: true},
+		{name: "fractional seconds", setting: customTime, input: "1.5s", wantErr: true},
+		{name: "words", setting: customWords, input: "200", want: 200},
+		{name: "too many words", setting: customWords, input: "501", wantErr: true},
+		{name: "no words", setting: customWords, input: "0", wantErr: true},
+		{name: "not a number", setting: customWords, input: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCustomValue(database.DefaultConfig, tt.setting, tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected %q to be rejected, got %d", tt.input, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("expected %d, got %d (err %v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestTimerSettingsCustomEntry(t *testing.T) {
+	user := &database.ApplicationUser{Config: &database.UserConfig{Time: 300, Words: 30}}
+	h := NewSettingsHandler(user)
+
+	timer := h.settingSelections[0].(*TimerSettings)
+	if !timer.isCustom() || timer.custom != 300 {
+		t.Fatalf("expected a 300s timer to select the custom entry, got cursor %d custom %d", timer.selectionCursor, timer.custom)
+	}
+	timer.MoveRight()
+	if timer.selectionCursor != 0 {
+		t.Errorf("expected moving right from custom to wrap to the first preset, got %d", timer.selectionCursor)
+	}
+	timer.MoveLeft()
+	if !timer.isCustom() {
+		t.Errorf("expected moving left from the first preset to reach custom, got %d", timer.selectionCursor)
+	}
+
+	words := h.settingSelections[1].(*WordsSettings)
+	if words.isCustom() || words.custom != 0 {
+		t.Errorf("expected 30 words to select a preset, got cursor %d custom %d", words.selectionCursor, words.custom)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"unicode"
+
+	"termtyper/database"
+)
+
+const (
+	failModeOff = "off"
+	// failModeSuddenDeath fails a test on the first wrong key.
+	failModeSuddenDeath = "sudden_death"
+	// failModePerfectionist fails a test when a word is left behind with an
+	// error in it that wasn't corrected.
+	failModePerfectionist = "perfectionist"
+)
+
+var failModeNames = []string{failModeOff, failModeSuddenDeath, failModePerfectionist}
+
+var failModeLabels = map[string]string{
+	failModeOff:           "off",
+	failModeSuddenDeath:   "sudden death",
+	failModePerfectionist: "perfectionist",
+}
+
+// failWarmupSeconds is how long a test runs before its speed and accuracy
+// floors apply. Over the first seconds, and the first word, there is too
+// little typed for either to say anything.
+const failWarmupSeconds = 3
+
+// minWpmSteps and minAccuracySteps are the floors offered in settings; 0 is
+// no floor.
+var (
+	minWpmSteps      = []int{0, 20, 40, 60, 80, 100, 120}
+	minAccuracySteps = []int{0, 80, 90, 95, 98, 100}
+)
+
+// failureRules are the conditions that end a test early as failed.
+type failureRules struct {
+	mode        string
+	minWpm      int
+	minAccuracy int
+}
+
+func newFailureRules(config *database.UserConfig) failureRules {
+	return failureRules{
+		mode:        config.FailMode,
+		minWpm:      config.MinWpm,
+		minAccuracy: config.MinAccuracy,
+	}
+}
+
+// checkKeystroke is called after every typed key with the mistake count from
+// before it, and fails the test if the key broke the fail mode. It reports
+// whether the test has failed.
+func (base *TestBase) checkKeystroke(mistakesBefore int) bool {
+	switch base.failRules.mode {
+	case failModeSuddenDeath:
+		if base.mistakes.rawMistakesCnt > mistakesBefore {
+			base.failed = "wrong key"
+		}
+	case failModePerfectionist:
+		if base.leftWordWithError() {
+			base.failed = "moved on with an uncorrected error"
+		}
+	}
+	return base.failed != ""
+}
+
+// leftWordWithError reports whether the key just typed was the separator
+// after a word while something typed so far is still wrong.
+func (base *TestBase) leftWordWithError() bool {
+	position := len(base.inputBuffer) - 1
+	if position < 0 || position >= len(base.wordsToEnter) {
+		return false
+	}
+	expected := base.wordsToEnter[position]
+	return unicode.IsSpace(expected) && base.inputBuffer[position] == expected && base.hasMistakes()
+}
+
+// typedFirstWord reports whether the input has got past the first word of the
+// text.
+func (base *TestBase) typedFirstWord() bool {
+	for _, r := range base.wordsToEnter[:len(base.inputBuffer)] {
+		if unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSecond is called each time a second's WPM is added to wpmEachSecond
+// and fails the test if the speed or accuracy so far is under its floor. The
+// floors apply once the warm-up seconds are over and the first word is typed.
+func (base *TestBase) checkSecond() bool {
+	if len(base.wpmEachSecond) < failWarmupSeconds || !base.typedFirstWord() {
+		return false
+	}
+
+	wpm := base.wpmEachSecond[len(base.wpmEachSecond)-1]
+	accuracy := base.calculateAccuracy()
+	switch {
+	case base.failRules.minWpm > 0 && wpm < float64(base.failRules.minWpm):
+		base.failed = fmt.Sprintf("%.0f WPM is under the %d WPM minimum", wpm, base.failRules.minWpm)
+	case base.failRules.minAccuracy > 0 && accuracy < float64(base.failRules.minAccuracy):
+		base.failed = fmt.Sprintf("%.1f%% accuracy is under the %d%% minimum", accuracy, base.failRules.minAccuracy)
+	}
+	return base.failed != ""
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// typeUntilFailed types text one key at a time, as the test handlers do, and
+// returns how many keys went in before the test failed.
+func typeUntilFailed(base *TestBase, text string) int {
+	for i, r := range text {
+		mistakesBefore := base.mistakes.rawMistakesCnt
+		handleCharacterInputFromMsg(tea.KeyPressMsg{Code: r, Text: string(r)}, base)
+		if base.checkKeystroke(mistakesBefore) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func TestSuddenDeath(t *testing.T) {
+	base := newTestBase("the quick fox")
+	base.failRules = failureRules{mode: failModeSuddenDeath}
+
+	if keys := typeUntilFailed(&base, "the qu1ck"); keys != 7 {
+		t.Errorf("expected to fail on the 7th key, failed after %d", keys)
+	}
+	if base.failed != "wrong key" {
+		t.Errorf("expected reason %q, got %q", "wrong key", base.failed)
+	}
+}
+
+func TestPerfectionist(t *testing.T) {
+	base := newTestBase("the quick fox")
+	base.failRules = failureRules{mode: failModePerfectionist}
+
+	if keys := typeUntilFailed(&base, "thw"); keys != -1 {
+		t.Fatalf("expected a wrong key inside a word to be allowed, failed after %d", keys)
+	}
+	handleBackspace(&base)
+	if keys := typeUntilFailed(&base, "e quack "); keys != 8 {
+		t.Errorf("expected to fail on leaving the second word, failed after %d", keys)
+	}
+}
+
+func TestPerfectionistWordInputSkip(t *testing.T) {
+	base := newTestBase("the quick fox")
+	base.wordInput = true
+	base.failRules = failureRules{mode: failModePerfectionist}
+
+	if keys := typeUntilFailed(&base, "the qu "); keys != 7 {
+		t.Errorf("expected skipping the rest of a word to fail, failed after %d", keys)
+	}
+}
+
+func TestCheckSecond(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    failureRules
+		seconds  int
+		wpm      float64
+		typed    string
+		wantFail bool
+	}{
+		{name: "no floors", rules: failureRules{}, seconds: 5, wpm: 5, typed: "thx q", wantFail: false},
+		{name: "over minimum wpm", rules: failureRules{minWpm: 40}, seconds: 5, wpm: 45, typed: "the q", wantFail: false},
+		{name: "under minimum wpm", rules: failureRules{minWpm: 40}, seconds: 5, wpm: 39, typed: "the q", wantFail: true},
+		{name: "under minimum accuracy", rules: failureRules{minAccuracy: 95}, seconds: 5, wpm: 80, typed: "thx q", wantFail: true},
+		{name: "during warm-up", rules: failureRules{minWpm: 40}, seconds: failWarmupSeconds - 1, wpm: 10, typed: "the q", wantFail: false},
+		{name: "in the first word", rules: failureRules{minWpm: 40}, seconds: 5, wpm: 0, typed: "th", wantFail: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newTestBase("the quick fox")
+			base.failRules = tt.rules
+			typeText(&base, tt.typed)
+			for range tt.seconds {
+				base.wpmEachSecond = append(base.wpmEachSecond, tt.wpm)
+			}
+
+			if got := base.checkSecond(); got != tt.wantFail {
+				t.Errorf("expected failed %t, got %t (%q)", tt.wantFail, got, base.failed)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"termtyper/database"
+)
+
+// ghost plays back a stored attempt at the same text while the user types,
+// as a second caret. The attempt is replayed into its own copy of the test
+// so that backspaces and word-input skips move it the way they did live.
+type ghost struct {
+	record []KeyPress
+	replay TestBase
+	next   int
+	// elapsed is how far into the record the ghost has been played.
+	elapsed int64
+}
+
+func newGhost(record []KeyPress, wordInput bool) *ghost {
+	g := &ghost{record: record}
+	g.replay.wordInput = wordInput
+	g.reset()
+	return g
+}
+
+func (g *ghost) reset() {
+	g.replay.inputBuffer = make([]rune, 0)
+	g.replay.cursor = 0
+	g.replay.rawInputCount = 0
+	g.replay.mistakes = mistakes{mistakesAt: make(map[int]bool)}
+	g.replay.extra = nil
+	g.next = 0
+	g.elapsed = 0
+}
+
+// advance plays the presses made in the first elapsed milliseconds of the
+// record over text and returns where the ghost's cursor is. Going back in
+// time, as watching a replay of the race does, starts the ghost over.
+func (g *ghost) advance(text []rune, elapsed int64) int {
+	if elapsed < g.elapsed {
+		g.reset()
+	}
+	g.elapsed = elapsed
+	g.replay.wordsToEnter = text
+	for g.next < len(g.record) && g.record[g.next].timestamp <= elapsed {
+		switch key := g.record[g.next].key; key {
+		case '\b':
+			handleBackspace(&g.replay)
+		default:
+			handleCharacterInputFromRune(key, &g.replay)
+		}
+		g.next++
+	}
+	return len(g.replay.inputBuffer)
+}
+
+// reachedAt replays record over text and returns when it first got to
+// position, or false if it never did.
+func reachedAt(text []rune, record []KeyPress, wordInput bool, position int) (int64, bool) {
+	if position <= 0 {
+		return 0, false
+	}
+	g := newGhost(record, wordInput)
+	for _, press := range record {
+		if g.advance(text, press.timestamp) >= position {
+			return press.timestamp, true
+		}
+	}
+	return 0, false
+}
+
+// ghostGap compares a finished race: how much sooner (negative) or later the
+// user got to the furthest point both they and the ghost reached. The ghost
+// is played back in the input mode it was typed in.
+func ghostGap(base TestBase) (time.Duration, bool) {
+	g := base.pace.ghost
+	if g == nil {
+		return 0, false
+	}
+	full := newGhost(g.record, g.replay.wordInput)
+	ghostEnd := full.advance(base.wordsToEnter, g.record[len(g.record)-1].timestamp)
+	position := min(len(base.inputBuffer), ghostEnd)
+
+	mine, ok := reachedAt(base.wordsToEnter, base.testRecord, base.wordInput, position)
+	if !ok {
+		return 0, false
+	}
+	theirs, ok := reachedAt(base.wordsToEnter, g.record, g.replay.wordInput, position)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(mine-theirs) * time.Millisecond, true
+}
+
+// raceResult describes how a finished race against a ghost went, or is
+// empty when the test wasn't a race.
+func raceResult(base TestBase) string {
+	gap, ok := ghostGap(base)
+	if !ok {
+		return ""
+	}
+	return formatGhostGap(gap)
+}
+
+// formatGhostGap describes the result of a race against a ghost.
+func formatGhostGap(gap time.Duration) string {
+	switch {
+	case gap < 0:
+		return fmt.Sprintf("Ghost: %s ahead", formatDuration(-gap))
+	case gap > 0:
+		return fmt.Sprintf("Ghost: %s behind", formatDuration(gap))
+	}
+	return "Ghost: dead heat"
+}
+
+// keptAttempt is an attempt kept for racing: its key presses and the input
+// mode they were typed in.
+type keptAttempt struct {
+	record    []KeyPress
+	wordInput bool
+}
+
+// saveReplay keeps the key presses of a seeded test so it can be raced later.
+// Voided and failed tests aren't kept. Guests keep theirs in the session.
+func saveReplay(context *StateContext, testID int64, seed string, base TestBase) {
+	if base.integrity.void || base.failed != "" || len(base.testRecord) == 0 {
+		return
+	}
+
+	session := context.model.session
+	userID := session.User.Id
+	if userID > 0 {
+		if testID > 0 {
+			replay := database.Replay{Seed: seed, WordInput: base.wordInput}
+			for _, press := range base.testRecord {
+				replay.Keys = append(replay.Keys, database.ReplayKey{Key: press.key, Ms: press.timestamp})
+			}
+			_ = database.SaveReplay(context.model.context.UserRepository, userID, testID, replay)
+		}
+		return
+	}
+
+	if session.Replays == nil {
+		session.Replays = make(map[string]keptAttempt)
+	}
+	session.Replays[seed] = keptAttempt{record: base.testRecord, wordInput: base.wordInput}
+}
+
+// loadGhost returns the latest kept attempt at the text of seed, which has
+// no key presses when there is none.
+func loadGhost(m *model, seed string) keptAttempt {
+	session := m.session
+	userID := session.User.Id
+	if userID > 0 {
+		replay, err := database.GetLatestReplay(m.context.UserRepository, userID, seed)
+		if err != nil || replay == nil {
+			return keptAttempt{}
+		}
+		attempt := keptAttempt{wordInput: replay.WordInput}
+		for _, key := range replay.Keys {
+			attempt.record = append(attempt.record, KeyPress{key: key.Key, timestamp: key.Ms})
+		}
+		return attempt
+	}
+
+	return session.Replays[seed]
+}
+
+// NewGhostRaceHandler starts a test on the text of seed with the user's
+// latest kept attempt at it playing alongside. Without one it is a plain
+// seeded test.
+func NewGhostRaceHandler(menu MainMenuHandler, m *model, code string) StateHandler {
+	seed, err := ParseSeedCode(code)
+	if err != nil {
+		return NewMainMenuHandler(m.session.User, m)
+	}
+
+	var racer *ghost
+	if attempt := loadGhost(m, code); len(attempt.record) > 0 {
+		racer = newGhost(attempt.record, attempt.wordInput)
+	}
+	return NewSeededTestHandler(menu, seed, racer)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// recordTyping types text with a press every interval, as a test handler
+// would record it.
+func recordTyping(base *TestBase, text string, interval int64) {
+	for i, r := range text {
+		timestamp := int64(i) * interval
+		if r == '\b' {
+			handleBackspace(base)
+		} else {
+			handleCharacterInputFromRune(r, base)
+		}
+		base.testRecord = append(base.testRecord, KeyPress{key: r, timestamp: timestamp})
+	}
+}
+
+func TestGhostAdvance(t *testing.T) {
+	text := []rune("the quick fox")
+	attempt := newTestBase(string(text))
+	recordTyping(&attempt, "thw\be q", 100)
+
+	g := newGhost(attempt.testRecord, false)
+	if got := g.advance(text, 250); got != 3 {
+		t.Errorf("expected the ghost at 3 after 250ms, got %d", got)
+	}
+	if got := g.advance(text, 350); got != 2 {
+		t.Errorf("expected the ghost's backspace to move it back to 2, got %d", got)
+	}
+	if got := g.advance(text, 10000); got != 5 {
+		t.Errorf("expected the ghost to finish at 5, got %d", got)
+	}
+	if got := g.advance(text, 50); got != 1 {
+		t.Errorf("expected going back in time to start the ghost over, got %d", got)
+	}
+}
+
+func TestGhostPaceCaret(t *testing.T) {
+	attempt := newTestBase("the quick fox")
+	recordTyping(&attempt, "the q", 100)
+
+	base := newTestBase("the quick fox")
+	base.pace.ghost = newGhost(attempt.testRecord, false)
+	typeText(&base, "th")
+	base.movePace(300 * time.Millisecond)
+
+	if !base.paceAt(4) {
+		t.Errorf("expected the ghost caret at 4, got %d", base.pace.cursor)
+	}
+	if base.paceAt(2) {
+		t.Error("expected no ghost caret at the cursor")
+	}
+}
+
+func TestGhostGap(t *testing.T) {
+	ghostRun := newTestBase("the fox")
+	recordTyping(&ghostRun, "the fox", 200)
+
+	tests := []struct {
+		name     string
+		typed    string
+		interval int64
+		want     time.Duration
+	}{
+		{name: "ahead", typed: "the fox", interval: 150, want: -300 * time.Millisecond},
+		{name: "behind", typed: "the fox", interval: 250, want: 300 * time.Millisecond},
+		{name: "unfinished", typed: "the", interval: 100, want: -200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newTestBase("the fox")
+			base.pace.ghost = newGhost(ghostRun.testRecord, false)
+			recordTyping(&base, tt.typed, tt.interval)
+
+			gap, ok := ghostGap(base)
+			if !ok || gap != tt.want {
+				t.Errorf("expected a gap of %v, got %v (ok %t)", tt.want, gap, ok)
+			}
+		})
+	}
+
+	if _, ok := ghostGap(newTestBase("the fox")); ok {
+		t.Error("expected no gap without a ghost")
+	}
+}
+
+func TestGhostGapUsesGhostInputMode(t *testing.T) {
+	// The ghost skipped the rest of "the" with word input, so its six
+	// presses cover all seven characters.
+	ghostRun := newTestBase("the fox")
+	ghostRun.wordInput = true
+	recordTyping(&ghostRun, "th fox", 100)
+
+	base := newTestBase("the fox")
+	base.pace.ghost = newGhost(ghostRun.testRecord, true)
+	recordTyping(&base, "the fox", 100)
+
+	gap, ok := ghostGap(base)
+	if !ok || gap != 100*time.Millisecond {
+		t.Errorf("expected to finish 100ms behind, got %v (ok %t)", gap, ok)
+	}
+}
+
+func TestGuestReplayUnderSessionLock(t *testing.T) {
+	m := newGuestModel()
+	context := &StateContext{model: m}
+	base := newTestBase("the fox")
+	base.wordInput = true
+	recordTyping(&base, "th fox", 100)
+
+	var attempt keptAttempt
+	underSessionLock(t, m, func() {
+		saveReplay(context, 0, "w2-abc", base)
+		attempt = loadGhost(m, "w2-abc")
+	})
+	if len(attempt.record) != 6 || !attempt.wordInput {
+		t.Errorf("expected the guest's attempt and its input mode to be kept, got %+v", attempt)
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"termtyper/database"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// historyRecentTests is how many tests the history screen lists.
+const historyRecentTests = 15
+
+// HistoryHandler lists a user's latest tests, filtered by test length. The
+// filters are every timer and word count length the user has taken, custom
+// ones included, after "all tests".
+type HistoryHandler struct {
+	*BaseStateHandler
+	filters     []database.HistoryFilter
+	filterIndex int
+	records     []database.TestRecord
+	err         error
+}
+
+func NewHistoryHandler(m *model) *HistoryHandler {
+	h := &HistoryHandler{
+		BaseStateHandler: NewBaseStateHandler(StateHistory),
+		filters:          []database.HistoryFilter{{}},
+	}
+
+	user := m.session.User
+	if user.Id <= 0 {
+		return h
+	}
+
+	filters, err := database.GetHistoryFilters(m.context.UserRepository, user.Id)
+	if err != nil {
+		h.err = err
+		return h
+	}
+	h.filters = append(h.filters, filters...)
+	h.load(m)
+	return h
+}
+
+func (h *HistoryHandler) load(m *model) {
+	h.records, h.err = database.GetFilteredTestHistory(m.context.UserRepository, m.session.User.Id, h.filters[h.filterIndex], historyRecentTests)
+}
+
+func (h *HistoryHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+q":
+			if h.ValidateTransition(StateMainMenu, context) {
+				return NewMainMenuHandler(context.model.session.User, context.model), nil
+			}
+
+		case "left", "h":
+			if len(h.filters) < 2 {
+				break
+			}
+			if h.filterIndex == 0 {
+				h.filterIndex = len(h.filters) - 1
+			} else {
+				h.filterIndex--
+			}
+			h.load(context.model)
+
+		case "right", "l":
+			if len(h.filters) < 2 {
+				break
+			}
+			if h.filterIndex == len(h.filters)-1 {
+				h.filterIndex = 0
+			} else {
+				h.filterIndex++
+			}
+			h.load(context.model)
+		}
+	}
+	return h, nil
+}
+
+// historyFilterLabel names a filter the way the settings screen names test
+// lengths.
+func historyFilterLabel(filter database.HistoryFilter) string {
+	switch filter.TestType {
+	case "":
+		return "all tests"
+	case "timer":
+		return "timer " + formatSettingsDuration(filter.TestValue)
+	case "words":
+		return strconv.Itoa(filter.TestValue) + " words"
+	}
+	return filter.TestType + " " + strconv.Itoa(filter.TestValue)
+}
+
+func historyRecordLine(record database.TestRecord) string {
+	test := historyFilterLabel(database.HistoryFilter{TestType: record.TestType, TestValue: record.TestValue})
+	line := fmt.Sprintf("%s  %-14s %4.0f wpm  %5.1f%%",
+		record.CreatedAt.Format("2006-01-02 15:04"), test, record.WPM, record.Accuracy)
+	if record.Failed {
+		line += "  failed"
+	} else if record.Flagged != "" {
+		line += "  flagged"
+	}
+	return line
+}
+
+func (h *HistoryHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+
+	title := style("History", m.styles.themeFunc)
+	title = lipgloss.NewStyle().PaddingBottom(1).Render(title)
+
+	filter := "[" + style(historyFilterLabel(h.filters[h.filterIndex]), m.styles.themeFunc) + "]"
+	items := []string{lipgloss.NewStyle().PaddingBottom(1).Render("Showing " + filter)}
+
+	switch {
+	case m.session.User.Id <= 0:
+		items = append(items, style("Log in to keep a history of your tests", m.styles.toEnter))
+	case h.err != nil:
+		items = append(items, style(h.err.Error(), m.styles.mistake))
+	case len(h.records) == 0:
+		items = append(items, style("No tests yet", m.styles.toEnter))
+	}
+	for _, record := range h.records {
+		items = append(items, style(historyRecordLine(record), m.styles.toEnter))
+	}
+
+	helpText := lipgloss.NewStyle().Faint(true).Render("\nleft/right: filter by test, esc/ctrl+q: back")
+
+	joined := lipgloss.JoinVertical(lipgloss.Left, append([]string{title}, items...)...)
+	joined = lipgloss.JoinVertical(lipgloss.Left, joined, helpText)
+	s := lipgloss.NewStyle().Align(lipgloss.Left).Render(joined)
+
+	return lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, s)
+}
+
+func (h *HistoryHandler) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/database"
+)
+
+func TestHistoryFilterLabel(t *testing.T) {
+	tests := []struct {
+		filter database.HistoryFilter
+		want   string
+	}{
+		{filter: database.HistoryFilter{}, want: "all tests"},
+		{filter: database.HistoryFilter{TestType: "timer", TestValue: 300}, want: "timer 5m0s"},
+		{filter: database.HistoryFilter{TestType: "words", TestValue: 200}, want: "200 words"},
+	}
+
+	for _, tt := range tests {
+		if got := historyFilterLabel(tt.filter); got != tt.want {
+			t.Errorf("historyFilterLabel(%+v) = %q, expected %q", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryForGuests(t *testing.T) {
+	h := NewHistoryHandler(newGuestModel())
+	if len(h.filters) != 1 || h.records != nil || h.err != nil {
+		t.Errorf("expected guests to get only the all-tests filter, got %+v", h)
+	}
+}
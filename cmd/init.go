@@ -18,9 +18,18 @@ func createStyles(termProfile termenv.Profile, foregroundColor termenv.Color, th
 		mistake: func(str string) termenv.Style {
 			return termenv.String(str).Foreground(termProfile.Color("1"))
 		},
+		extra: func(str string) termenv.Style {
+			return termenv.String(str).Foreground(termProfile.Color("1")).Faint()
+		},
+		missed: func(str string) termenv.Style {
+			return termenv.String(str).Foreground(termProfile.Color("1")).Underline()
+		},
 		cursor: func(str string) termenv.Style {
 			return termenv.String(str).Reverse().Bold()
 		},
+		pace: func(str string) termenv.Style {
+			return termenv.String(str).Background(termProfile.Color(themeColor))
+		},
 		themeFunc: func(str string) termenv.Style {
 			return termenv.String(str).Foreground(termProfile.Color(themeColor))
 		},
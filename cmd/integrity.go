@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+const (
+	// burstKeys key presses landing within burstWindowMs of each other is
+	// faster than anyone types, around 2000 WPM, and means the keys weren't
+	// typed one by one as they arrived.
+	burstKeys     = 10
+	burstWindowMs = 50
+	// burstVoidShare is how much of a test has to arrive in bursts to void
+	// it. Key times are when the keys reached us, and a stalled SSH session or
+	// terminal hands over everything typed during the stall at once, so a few
+	// bursts in an otherwise typed test only flag it.
+	burstVoidShare = 0.5
+)
+
+// testIntegrity is the verdict on whether a test was typed by hand. Flagged
+// tests are saved with the reason; void ones aren't saved at all.
+type testIntegrity struct {
+	reason string
+	void   bool
+}
+
+// notePaste counts a paste the terminal announced. The pasted text is never
+// typed into the test.
+func notePaste(msg tea.PasteMsg, base *TestBase) {
+	if msg.Content != "" {
+		base.pasteAttempts++
+	}
+}
+
+// checkIntegrity looks over a finished test for signs it wasn't typed. Runs of
+// key presses closer together than a person can manage flag it, and void it
+// when they make up most of the test; pastes, which are kept out of the input
+// but still attempted, flag it.
+func checkIntegrity(base TestBase) testIntegrity {
+	if bursts := findBursts(base.testRecord); len(bursts) > 0 {
+		keys := 0
+		for _, run := range bursts {
+			keys += run.keys
+		}
+		reason := fmt.Sprintf("%d keys in %dms", bursts[0].keys, bursts[0].ms)
+		if len(bursts) > 1 {
+			reason = fmt.Sprintf("%d keys in %d bursts", keys, len(bursts))
+		}
+		return testIntegrity{
+			reason: reason,
+			void:   float64(keys) >= burstVoidShare*float64(len(base.testRecord)),
+		}
+	}
+
+	if base.pasteAttempts > 0 {
+		return testIntegrity{reason: "tried to paste text"}
+	}
+
+	return testIntegrity{}
+}
+
+// burst is a run of key presses that arrived faster than anyone types.
+type burst struct {
+	keys int
+	ms   int64
+}
+
+// findBursts returns every run of at least burstKeys presses within
+// burstWindowMs, each extended for as long as the presses keep coming that
+// fast.
+func findBursts(record []KeyPress) []burst {
+	var bursts []burst
+	for start := 0; start+burstKeys <= len(record); start++ {
+		end := start + burstKeys - 1
+		if record[end].timestamp-record[start].timestamp > burstWindowMs {
+			continue
+		}
+		for end+1 < len(record) && record[end+1].timestamp-record[end+1-burstKeys+1].timestamp <= burstWindowMs {
+			end++
+		}
+		bursts = append(bursts, burst{keys: end - start + 1, ms: record[end].timestamp - record[start].timestamp})
+		start = end
+	}
+	return bursts
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestCheckIntegrityBurst(t *testing.T) {
+	base := newTestBase("the quick brown fox")
+	var timestamp int64
+	for i, r := range "the quick brown fox" {
+		// A person types the first word, then the rest arrives 2ms apart.
+		if i < 4 {
+			timestamp += 120
+		} else {
+			timestamp += 2
+		}
+		base.testRecord = append(base.testRecord, KeyPress{key: r, timestamp: timestamp})
+	}
+
+	integrity := checkIntegrity(base)
+	if !integrity.void {
+		t.Fatal("a burst of key presses should void the test")
+	}
+	if integrity.reason != "16 keys in 30ms" {
+		t.Errorf("unexpected reason %q", integrity.reason)
+	}
+}
+
+func TestCheckIntegrityStallOnlyFlags(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog"
+	base := newTestBase(text)
+	var timestamp int64
+	for i, r := range text {
+		// Steady typing, with the keys of a stall in the middle all
+		// arriving together once it ends.
+		if i >= 20 && i < 32 {
+			timestamp += 1
+		} else {
+			timestamp += 150
+		}
+		base.testRecord = append(base.testRecord, KeyPress{key: r, timestamp: timestamp})
+	}
+
+	integrity := checkIntegrity(base)
+	if integrity.void {
+		t.Error("a single stall should not void a typed test")
+	}
+	if integrity.reason != "13 keys in 12ms" {
+		t.Errorf("the burst should still flag the test, got %+v", integrity)
+	}
+}
+
+func TestCheckIntegrityHumanTyping(t *testing.T) {
+	base := newTestBase("the quick brown fox")
+	var timestamp int64
+	for i, r := range "the quick brown fox" {
+		// Fast rolls between some keys, but never a long run of them.
+		if i%3 == 0 {
+			timestamp += 90
+		} else {
+			timestamp += 8
+		}
+		base.testRecord = append(base.testRecord, KeyPress{key: r, timestamp: timestamp})
+	}
+
+	if integrity := checkIntegrity(base); integrity.reason != "" {
+		t.Errorf("human typing should pass, got %+v", integrity)
+	}
+}
+
+func TestCheckIntegrityPaste(t *testing.T) {
+	base := newTestBase("hello world")
+	handleCharacterInputFromMsg(tea.KeyPressMsg{Text: "hello world"}, &base)
+	notePaste(tea.PasteMsg{Content: "world"}, &base)
+
+	if len(base.inputBuffer) != 0 {
+		t.Errorf("pasted text should not be typed, got %q", string(base.inputBuffer))
+	}
+	if base.pasteAttempts != 2 {
+		t.Errorf("expected 2 paste attempts, got %d", base.pasteAttempts)
+	}
+
+	integrity := checkIntegrity(base)
+	if integrity.void || integrity.reason == "" {
+		t.Errorf("a blocked paste should flag the test without voiding it, got %+v", integrity)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"termtyper/database"
+	"termtyper/words"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+)
+
+type KeyFilterKeysHandler struct {
+	*BaseStateHandler
+	form *huh.Form
+	keys *string
+}
+
+// NewKeyFilterKeysHandler edits the letters allowed by the custom key filter.
+func NewKeyFilterKeysHandler(config *database.UserConfig) *KeyFilterKeysHandler {
+	keys := new(string)
+	*keys = config.KeyFilterKeys
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Allowed letters").
+				Placeholder("asdfjkl").
+				Value(keys).
+				Validate(func(str string) error {
+					if words.NormalizeKeys(str) == "" {
+						return fmt.Errorf("enter at least one letter")
+					}
+					if len(str) > 64 {
+						return fmt.Errorf("enter at most 64 characters")
+					}
+					return nil
+				}),
+		),
+	)
+
+	return &KeyFilterKeysHandler{
+		BaseStateHandler: NewBaseStateHandler(StateKeyFilterKeys),
+		form:             form,
+		keys:             keys,
+	}
+}
+
+func (h *KeyFilterKeysHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+q":
+			if h.ValidateTransition(StateSettings, context) {
+				return NewSettingsHandler(context.model.session.User), nil
+			}
+		}
+	}
+
+	var commands []tea.Cmd
+	updatedForm, formCmd := h.form.Update(msg)
+	if f, ok := updatedForm.(*huh.Form); ok {
+		h.form = f
+		commands = append(commands, formCmd)
+	}
+
+	if h.form.State == huh.StateCompleted {
+		newUserConfig := context.model.session.User.Config
+		newUserConfig.KeyFilter = words.CustomKeyFilter
+		newUserConfig.KeyFilterKeys = words.NormalizeKeys(*h.keys)
+
+		database.UpdateUserConfigStandalone(
+			context.model.context.UserRepository,
+			context.model.session.User.Id,
+			UserConfigToMap(newUserConfig))
+
+		if h.ValidateTransition(StateSettings, context) {
+			return NewSettingsHandler(context.model.session.User), nil
+		}
+	}
+
+	return h, tea.Batch(commands...)
+}
+
+func (h *KeyFilterKeysHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+
+	title := style("Custom Key Filter", m.styles.themeFunc)
+	title = lipgloss.NewStyle().PaddingBottom(1).Render(title)
+
+	helpText := lipgloss.NewStyle().Faint(true).Render("enter: save • esc/ctrl+q: back without saving")
+
+	joined := lipgloss.JoinVertical(lipgloss.Left, title, h.form.View(), "", helpText)
+	s := lipgloss.NewStyle().Align(lipgloss.Left).Render(joined)
+	centeredText := lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, s)
+
+	return centeredText
+}
+
+func (h *KeyFilterKeysHandler) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
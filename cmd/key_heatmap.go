@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"termtyper/database"
+
+	"charm.land/lipgloss/v2"
+)
+
+// heatmapRecentTests is how many of a user's latest tests the aggregated
+// heatmap covers.
+const heatmapRecentTests = 50
+
+var heatmapRows = []string{"qwertyuiop", "asdfghjkl;", "zxcvbnm,./"}
+
+// heatmapOtherRowLen is how many keys off the QWERTY rows, such as those of
+// the non-Latin languages, are shown per row under the space bar.
+const heatmapOtherRowLen = 10
+
+// heatmapColors run from fast and accurate to slow and error-prone.
+var heatmapColors = []string{"22", "28", "34", "70", "106", "142", "178", "172", "166", "160", "124"}
+
+// collectKeyStats replays a test's key presses and records, for every key of
+// the text, how often it was attempted and mistyped and how long it took after
+// the press before it. Presses right after a backspace aren't timed.
+func collectKeyStats(base TestBase) []database.KeyStat {
+	stats := make(map[string]*database.KeyStat)
+	walkKeyRecord(base, func(typed typedKey) {
+		key := string(unicode.ToLower(typed.expected))
+		stat, ok := stats[key]
+		if !ok {
+			stat = &database.KeyStat{Key: key}
+			stats[key] = stat
+		}
+		stat.Presses++
+		if typed.press.key != typed.expected {
+			stat.Mistakes++
+		}
+		if typed.timed {
+			stat.TimedPresses++
+			stat.TotalMs += typed.sinceLast
+		}
+	})
+
+	result := make([]database.KeyStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+
+	return result
+}
+
+// testHeatmap is the heatmap of a single finished test, which the results
+// screen opens on.
+func testHeatmap(test TestBase) *KeyHeatmap {
+	return NewKeyHeatmap("Key Heatmap (this test)", collectKeyStats(test))
+}
+
+// saveKeyStats stores a finished test's key stats against its history record
+// so the aggregated heatmap can draw on them.
+func saveKeyStats(context *StateContext, testID int64, base TestBase) {
+	userID := context.model.session.User.Id
+	if userID <= 0 || testID <= 0 || base.integrity.void {
+		return
+	}
+	_ = database.SaveKeyStats(context.model.context.UserRepository, userID, testID, collectKeyStats(base))
+}
+
+type KeyHeatmap struct {
+	title string
+	stats map[string]database.KeyStat
+}
+
+func NewKeyHeatmap(title string, stats []database.KeyStat) *KeyHeatmap {
+	byKey := make(map[string]database.KeyStat, len(stats))
+	for _, stat := range stats {
+		if stat.Presses > 0 {
+			byKey[stat.Key] = stat
+		}
+	}
+	return &KeyHeatmap{title: title, stats: byKey}
+}
+
+func keyAverageMs(stat database.KeyStat) float64 {
+	if stat.TimedPresses == 0 {
+		return 0
+	}
+	return float64(stat.TotalMs) / float64(stat.TimedPresses)
+}
+
+func keyErrorRate(stat database.KeyStat) float64 {
+	return float64(stat.Mistakes) / float64(stat.Presses)
+}
+
+// keyWeakness scores a key like bigramWeakness does a pair. Keys that were
+// never timed are scored at the average latency so only their errors count.
+func keyWeakness(stat database.KeyStat, meanMs float64) float64 {
+	latency := keyAverageMs(stat)
+	if stat.TimedPresses == 0 {
+		latency = meanMs
+	}
+	return latency * (1 + 4*keyErrorRate(stat))
+}
+
+// heat places every key with stats between 0, the strongest key, and 1, the
+// weakest.
+func (hm *KeyHeatmap) heat() map[string]float64 {
+	var totalMs int64
+	timed := 0
+	for _, stat := range hm.stats {
+		totalMs += stat.TotalMs
+		timed += stat.TimedPresses
+	}
+	meanMs := 0.0
+	if timed > 0 {
+		meanMs = float64(totalMs) / float64(timed)
+	}
+
+	scores := make(map[string]float64, len(hm.stats))
+	low, high := 0.0, 0.0
+	first := true
+	for key, stat := range hm.stats {
+		score := keyWeakness(stat, meanMs)
+		scores[key] = score
+		if first || score < low {
+			low = score
+		}
+		if first || score > high {
+			high = score
+		}
+		first = false
+	}
+
+	for key, score := range scores {
+		if high > low {
+			scores[key] = (score - low) / (high - low)
+		} else {
+			scores[key] = 0
+		}
+	}
+	return scores
+}
+
+func (hm *KeyHeatmap) View() string {
+	if len(hm.stats) == 0 {
+		return lipgloss.NewStyle().Faint(true).Render("No key data available")
+	}
+
+	var result strings.Builder
+	result.WriteString(lipgloss.NewStyle().Bold(true).Render(hm.title) + "\n")
+
+	heat := hm.heat()
+	cell := func(key, label string) string {
+		h, ok := heat[key]
+		if !ok {
+			return lipgloss.NewStyle().Faint(true).Render(label)
+		}
+		color := heatmapColors[int(h*float64(len(heatmapColors)-1)+0.5)]
+		return lipgloss.NewStyle().
+			Background(lipgloss.Color(color)).
+			Foreground(lipgloss.Color("15")).
+			Render(label)
+	}
+
+	for i, row := range heatmapRows {
+		result.WriteString(strings.Repeat(" ", i))
+		for _, key := range row {
+			result.WriteString(cell(string(key), " "+string(key)+" "))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString(strings.Repeat(" ", 9) + cell(" ", strings.Repeat(" ", 12)) + "\n")
+
+	other := hm.otherKeys()
+	for start := 0; start < len(other); start += heatmapOtherRowLen {
+		for _, key := range other[start:min(start+heatmapOtherRowLen, len(other))] {
+			result.WriteString(cell(key, " "+key+" "))
+		}
+		result.WriteString("\n")
+	}
+
+	result.WriteString(lipgloss.NewStyle().Faint(true).Render(hm.summary()))
+
+	return result.String()
+}
+
+// otherKeys are the keys with stats that the QWERTY rows don't show, sorted.
+// Enter and tab are left out as they have no label to show.
+func (hm *KeyHeatmap) otherKeys() []string {
+	onRows := strings.Join(heatmapRows, "") + " "
+	var other []string
+	for key := range hm.stats {
+		r := []rune(key)
+		if len(r) != 1 || strings.ContainsRune(onRows, r[0]) || !unicode.IsGraphic(r[0]) {
+			continue
+		}
+		other = append(other, key)
+	}
+	sort.Strings(other)
+	return other
+}
+
+// summary names the slowest and the most mistyped key.
+func (hm *KeyHeatmap) summary() string {
+	var slowest, missed string
+	for key, stat := range hm.stats {
+		if stat.TimedPresses > 0 && (slowest == "" || keyAverageMs(stat) > keyAverageMs(hm.stats[slowest]) ||
+			keyAverageMs(stat) == keyAverageMs(hm.stats[slowest]) && key < slowest) {
+			slowest = key
+		}
+		if stat.Mistakes > 0 && (missed == "" || keyErrorRate(stat) > keyErrorRate(hm.stats[missed]) ||
+			keyErrorRate(stat) == keyErrorRate(hm.stats[missed]) && key < missed) {
+			missed = key
+		}
+	}
+
+	var parts []string
+	if slowest != "" {
+		parts = append(parts, fmt.Sprintf("slowest %s %.0fms", keyName(slowest), keyAverageMs(hm.stats[slowest])))
+	}
+	if missed != "" {
+		parts = append(parts, fmt.Sprintf("most missed %s %.0f%%", keyName(missed), keyErrorRate(hm.stats[missed])*100))
+	}
+	return strings.Join(parts, " • ")
+}
+
+func keyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"termtyper/database"
+)
+
+func TestCollectKeyStats(t *testing.T) {
+	base := newTestBase("Ab a")
+	base.testRecord = []KeyPress{
+		{key: 'A', timestamp: 0},
+		{key: 'x', timestamp: 300},
+		{key: '\b', timestamp: 400},
+		{key: 'b', timestamp: 600},
+		{key: ' ', timestamp: 700},
+		{key: 'a', timestamp: 750},
+	}
+
+	stats := make(map[string]database.KeyStat)
+	for _, stat := range collectKeyStats(base) {
+		stats[stat.Key] = stat
+	}
+
+	// "A" and "a" count as the same key; the first press of the test and the
+	// one after the backspace aren't timed.
+	if a := stats["a"]; a.Presses != 2 || a.Mistakes != 0 || a.TimedPresses != 1 || a.TotalMs != 50 {
+		t.Errorf("unexpected a stats: %+v", a)
+	}
+	if b := stats["b"]; b.Presses != 2 || b.Mistakes != 1 || b.TimedPresses != 1 || b.TotalMs != 300 {
+		t.Errorf("unexpected b stats: %+v", b)
+	}
+	if space := stats[" "]; space.Presses != 1 || space.TotalMs != 100 {
+		t.Errorf("unexpected space stats: %+v", space)
+	}
+}
+
+func TestKeyHeatmapSummary(t *testing.T) {
+	heatmap := NewKeyHeatmap("Key Heatmap", []database.KeyStat{
+		{Key: "e", Presses: 10, TimedPresses: 10, TotalMs: 1000},
+		{Key: "q", Presses: 4, Mistakes: 2, TimedPresses: 4, TotalMs: 800},
+		{Key: " ", Presses: 5, TimedPresses: 5, TotalMs: 1500},
+	})
+
+	heat := heatmap.heat()
+	if heat["e"] != 0 || heat["q"] != 1 {
+		t.Errorf("expected e to be the strongest key and q the weakest, got %v", heat)
+	}
+
+	summary := heatmap.summary()
+	if summary != "slowest space 300ms • most missed q 50%" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if !strings.Contains(heatmap.View(), "Key Heatmap") {
+		t.Error("heatmap view should show its title")
+	}
+}
+
+func TestKeyHeatmapShowsKeysOffTheQwertyRows(t *testing.T) {
+	heatmap := NewKeyHeatmap("Key Heatmap", []database.KeyStat{
+		{Key: "a", Presses: 3, TimedPresses: 3, TotalMs: 300},
+		{Key: "б", Presses: 2, TimedPresses: 2, TotalMs: 500},
+		{Key: "а", Presses: 4, Mistakes: 1, TimedPresses: 4, TotalMs: 400},
+		{Key: "\n", Presses: 1, TimedPresses: 1, TotalMs: 100},
+	})
+
+	if got := heatmap.otherKeys(); strings.Join(got, "") != "аб" {
+		t.Errorf("expected the Cyrillic keys in their own row, got %q", got)
+	}
+	if view := heatmap.View(); !strings.Contains(view, "а") || !strings.Contains(view, "б") {
+		t.Errorf("expected the heatmap to show the Cyrillic keys: %q", view)
+	}
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// renderLiveStats is the heads-up line under the timer: net and raw WPM,
+// accuracy and errors so far. It is shown from before the first key so the
+// text doesn't move when the test starts.
+func (base TestBase) renderLiveStats(elapsed time.Duration, styles Styles) string {
+	elapsedMinutes := elapsed.Minutes()
+	accuracy := 100.0
+	if base.rawInputCount > 0 {
+		accuracy = base.calculateAccuracy()
+	}
+	stats := fmt.Sprintf("%.0f wpm  %.0f raw  %.1f%% acc  %d errors",
+		base.calculateNormalizedWpm(elapsedMinutes),
+		base.calculateRawWpm(elapsedMinutes),
+		accuracy,
+		base.mistakes.rawMistakesCnt,
+	)
+	return style(stats, styles.toEnter)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muesli/termenv"
+)
+
+func TestRenderLiveStats(t *testing.T) {
+	styles := createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")
+	base := newTestBase("the quick fox")
+
+	if got := dropAnsiCodes(base.renderLiveStats(0, styles)); got != "0 wpm  0 raw  100.0% acc  0 errors" {
+		t.Errorf("unexpected stats before the first key: %q", got)
+	}
+
+	// "the " is four correct characters and "qx" one right and one wrong, so
+	// over six seconds that is 8 WPM net and 12 raw.
+	typeText(&base, "the qx")
+	got := dropAnsiCodes(base.renderLiveStats(6*time.Second, styles))
+	if got != "8 wpm  12 raw  83.3% acc  1 errors" {
+		t.Errorf("unexpected live stats: %q", got)
+	}
+}
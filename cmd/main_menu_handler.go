@@ -14,6 +14,7 @@ type MainMenuHandler struct {
 	cursor                 int
 	timerTestWordGenerator words.WordGenerator
 	wordTestWordGenerator  words.WordGenerator
+	textGenerator          words.WordGenerator
 	currentUser            *database.ApplicationUser
 }
 
@@ -28,16 +29,27 @@ func NewMainMenuHandler(user *database.ApplicationUser, m *model) *MainMenuHandl
 
 	timerGen := words.NewGenerator()
 	timerGen.Punctuation = user.Config.Punctuation
+	timerGen.Numbers = user.Config.Numbers
+	timerGen.Symbols = user.Config.Symbols
 
 	wordGen := words.NewGenerator()
 	wordGen.Punctuation = user.Config.Punctuation
+	wordGen.Numbers = user.Config.Numbers
+	wordGen.Symbols = user.Config.Symbols
 
 	return &MainMenuHandler{
 		BaseStateHandler: NewBaseStateHandler(StateMainMenu),
 		MainMenuSelection: []string{
 			"Timer",
 			"Word Count",
+			"Quote",
+			"Code",
+			"Book",
+			"Practice",
+			"Practice Mistakes",
 			"Zen",
+			"Type Seed",
+			"History",
 			"Config",
 			"User Settings",
 		},
@@ -45,6 +57,7 @@ func NewMainMenuHandler(user *database.ApplicationUser, m *model) *MainMenuHandl
 		cursor:                 0,
 		timerTestWordGenerator: timerGen,
 		wordTestWordGenerator:  wordGen,
+		textGenerator:          words.NewGenerator(),
 	}
 }
 
@@ -66,6 +79,36 @@ func (h *MainMenuHandler) HandleInput(msg tea.Msg, context *StateContext) (State
 				if h.ValidateTransition(StateWordCountTest, context) {
 					return NewWordCountTestHandler(*h), nil
 				}
+			case "Quote":
+				if h.ValidateTransition(StateQuoteTest, context) {
+					return NewQuoteTestHandler(*h), nil
+				}
+			case "Code":
+				if h.ValidateTransition(StateCodeTest, context) {
+					return NewCodeTestHandler(*h), nil
+				}
+			case "Book":
+				if h.ValidateTransition(StateBookSelect, context) {
+					return NewBookSelectHandler(*h), nil
+				}
+			case "Practice":
+				if h.ValidateTransition(StatePracticeTest, context) {
+					return NewPracticeTestHandler(*h, context.model), nil
+				}
+			case "Practice Mistakes":
+				if h.ValidateTransition(StateMistakesTest, context) {
+					return NewMistakesTestHandler(*h, recentMissedWords(context.model)), nil
+				}
+			case "Type Seed":
+				if h.ValidateTransition(StateSeedInput, context) {
+					seedInputHandler := NewSeedInputHandler(*h)
+					seedInputHandler.form.Init()
+					return seedInputHandler, nil
+				}
+			case "History":
+				if h.ValidateTransition(StateHistory, context) {
+					return NewHistoryHandler(context.model), nil
+				}
 			case "Config":
 				if h.ValidateTransition(StateSettings, context) {
 					return NewSettingsHandler(context.model.session.User), nil
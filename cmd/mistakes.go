@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"sort"
+	"unicode"
+
+	"termtyper/database"
+)
+
+const (
+	// mistakesRecentTests is how many of a user's latest tests feed the
+	// missed-words drill.
+	mistakesRecentTests = 5
+	mistakesMaxWords    = 20
+	// mistakesRepeats is how many times each missed word is typed.
+	mistakesRepeats = 3
+)
+
+// collectMissedWords replays a test's key presses and counts, for every word
+// of the text, how many wrong keys were typed in it, including ones later
+// corrected. A wrong key in place of the space after a word counts against
+// that word. Punctuation around a word is dropped so the drill practises the
+// word itself.
+func collectMissedWords(base TestBase) []database.MissedWord {
+	misses := make(map[string]int)
+	walkKeyRecord(base, func(typed typedKey) {
+		if typed.press.key == typed.expected {
+			return
+		}
+		if word := wordAt(base.wordsToEnter, typed.position); word != "" {
+			misses[word]++
+		}
+	})
+
+	missed := make([]database.MissedWord, 0, len(misses))
+	for word, count := range misses {
+		missed = append(missed, database.MissedWord{Word: word, Misses: count})
+	}
+	sort.Slice(missed, func(i, j int) bool {
+		if missed[i].Misses != missed[j].Misses {
+			return missed[i].Misses > missed[j].Misses
+		}
+		return missed[i].Word < missed[j].Word
+	})
+
+	return missed
+}
+
+// wordAt returns the word of text at position, or the word before it when
+// position is on whitespace, without leading or trailing punctuation.
+func wordAt(text []rune, position int) string {
+	end := position
+	for end > 0 && unicode.IsSpace(text[end]) {
+		end--
+	}
+	if unicode.IsSpace(text[end]) {
+		return ""
+	}
+
+	start := end
+	for start > 0 && !unicode.IsSpace(text[start-1]) {
+		start--
+	}
+	for end < len(text)-1 && !unicode.IsSpace(text[end+1]) {
+		end++
+	}
+
+	word := text[start : end+1]
+	for len(word) > 0 && !isWordRune(word[0]) {
+		word = word[1:]
+	}
+	for len(word) > 0 && !isWordRune(word[len(word)-1]) {
+		word = word[:len(word)-1]
+	}
+	return string(word)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func missedWordList(missed []database.MissedWord) []string {
+	var list []string
+	for _, word := range missed[:min(mistakesMaxWords, len(missed))] {
+		list = append(list, word.Word)
+	}
+	return list
+}
+
+// recentMissedWords loads the words the missed-words drill works from: those
+// of the user's recent tests when logged in, or of the last test for guests.
+func recentMissedWords(m *model) []string {
+	user := m.session.User
+	if user.Id > 0 {
+		missed, err := database.GetMissedWords(m.context.UserRepository, user.Id, mistakesRecentTests)
+		if err == nil {
+			return missedWordList(missed)
+		}
+	}
+
+	return missedWordList(m.session.MissedWords)
+}
+
+// saveMissedWords keeps a finished test's missed words on the session and,
+// when there is a history record to attach them to, in the database.
+func saveMissedWords(context *StateContext, testID int64, base TestBase) {
+	if base.integrity.void {
+		return
+	}
+
+	missed := collectMissedWords(base)
+
+	session := context.model.session
+	session.MissedWords = missed
+
+	userID := session.User.Id
+	if userID > 0 && testID > 0 {
+		_ = database.SaveMissedWords(context.model.context.UserRepository, userID, testID, missed)
+	}
+}
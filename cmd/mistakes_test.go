@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/database"
+)
+
+func TestCollectMissedWords(t *testing.T) {
+	base := newTestBase(`the "ox," ran`)
+	for _, key := range "thex\b \"oz\bc\bx,\" ran" {
+		base.testRecord = append(base.testRecord, KeyPress{key: key})
+	}
+
+	missed := collectMissedWords(base)
+
+	// The miss on the space belongs to "the", and the quotes and comma are
+	// dropped from "ox".
+	want := []database.MissedWord{{Word: "ox", Misses: 2}, {Word: "the", Misses: 1}}
+	if len(missed) != len(want) {
+		t.Fatalf("expected %v, got %v", want, missed)
+	}
+	for i := range want {
+		if missed[i] != want[i] {
+			t.Errorf("expected %v at %d, got %v", want[i], i, missed[i])
+		}
+	}
+}
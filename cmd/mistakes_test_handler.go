@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"termtyper/words"
+)
+
+type MistakesTestHandler struct {
+	*stopwatchTest
+	missed []string
+}
+
+// NewMistakesTestHandler builds a test that repeats each of the missed words
+// a few times. Without any it is a regular word count test.
+func NewMistakesTestHandler(menu MainMenuHandler, missed []string) *MistakesTestHandler {
+	menu.wordTestWordGenerator.Count = menu.currentUser.Config.Words
+	menu.wordTestWordGenerator.Punctuation = false
+	menu.wordTestWordGenerator.Numbers = false
+	menu.wordTestWordGenerator.Symbols = false
+	menu.wordTestWordGenerator.KeyFilter = words.KeyFilter{}
+	list := words.ResolveList(menu.currentUser.Config.WordList, menu.currentUser.Config.Language)
+
+	text := menu.wordTestWordGenerator.GenerateRepeats(missed, mistakesRepeats)
+	if len(missed) == 0 {
+		text = menu.wordTestWordGenerator.Generate(list)
+	}
+
+	h := &MistakesTestHandler{missed: missed}
+	h.stopwatchTest = newStopwatchTest(StateMistakesTest, menu, text, h)
+	return h
+}
+
+func (h *MistakesTestHandler) testType() string {
+	return "mistakes"
+}
+
+func (h *MistakesTestHandler) testValue() int {
+	return len(strings.Fields(string(h.base.wordsToEnter)))
+}
+
+func (h *MistakesTestHandler) label() string {
+	if len(h.missed) > 0 {
+		return fmt.Sprintf("practising %d missed words", len(h.missed))
+	}
+	return "no recent mistakes"
+}
+
+func (h *MistakesTestHandler) restart(context *StateContext) StateHandler {
+	return NewMistakesTestHandler(h.base.mainMenu, h.missed)
+}
+
+func (h *MistakesTestHandler) finish(context *StateContext, testID int64, results *ResultsHandler) {}
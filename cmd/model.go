@@ -30,6 +30,20 @@ type TestBase struct {
 	cursor        int
 	testRecord    []KeyPress
 	mainMenu      MainMenuHandler
+	// wordInput switches to word-based input: space skips to the next word,
+	// leaving the rest of the current one missed, and letters typed where a
+	// word has ended are kept in extra, keyed by the position of the
+	// separator they were typed over.
+	wordInput bool
+	extra     map[int][]rune
+	// pasteAttempts counts pastes kept out of the input, and integrity is
+	// the verdict checkIntegrity reached when the test finished.
+	pasteAttempts int
+	integrity     testIntegrity
+	// failRules can end the test early, and failed is why it did.
+	failRules failureRules
+	failed    string
+	pace      pace
 }
 
 type KeyPress struct {
@@ -49,12 +63,14 @@ type Timer struct {
 	isRunning  bool
 	startTime  time.Time
 	elapsed    time.Duration
+	pauseClock
 }
 
 type StopWatch struct {
 	stopwatch  stopwatch.Model
 	isRunning bool
 	startTime time.Time
+	pauseClock
 }
 
 type StringStyle func(string) termenv.Style
@@ -64,6 +80,9 @@ type Styles struct {
 	mistake  StringStyle
 	cursor   StringStyle
 	toEnter  StringStyle
+	extra    StringStyle
+	missed   StringStyle
+	pace     StringStyle
 	themeFunc StringStyle
 }
 
@@ -74,12 +93,12 @@ func (t *Timer) Elapsed() time.Duration {
 	if !t.isRunning {
 		return 0
 	}
-	return time.Since(t.startTime)
+	return time.Since(t.startTime) - t.pausedFor()
 }
 
 func (sw *StopWatch) Elapsed() time.Duration {
 	if !sw.isRunning {
 		return 0
 	}
-	return time.Since(sw.startTime)
+	return time.Since(sw.startTime) - sw.pausedFor()
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"termtyper/database"
+)
+
+const (
+	paceOff = "off"
+	// paceTarget runs the pace caret at a fixed WPM, and paceAverage and
+	// paceBest at the user's average and best for the test being taken.
+	paceTarget  = "target"
+	paceAverage = "average"
+	paceBest    = "best"
+)
+
+// paceOption is one choice of the pace caret setting.
+type paceOption struct {
+	mode string
+	wpm  int
+}
+
+var paceOptions = []paceOption{
+	{mode: paceOff},
+	{mode: paceAverage},
+	{mode: paceBest},
+	{mode: paceTarget, wpm: 40},
+	{mode: paceTarget, wpm: 60},
+	{mode: paceTarget, wpm: 80},
+	{mode: paceTarget, wpm: 100},
+	{mode: paceTarget, wpm: 120},
+}
+
+func (p paceOption) label() string {
+	switch p.mode {
+	case paceAverage:
+		return "average"
+	case paceBest:
+		return "personal best"
+	case paceTarget:
+		return fmt.Sprintf("%d wpm", p.wpm)
+	}
+	return "off"
+}
+
+// pace is a second caret that moves through the text at a steady speed, or
+// follows a ghost when racing one. It is hidden while wpm is 0 and there is
+// no ghost.
+type pace struct {
+	wpm    float64
+	cursor int
+	ghost  *ghost
+}
+
+// startPace sets the pace caret's speed when a test starts. Paced on the
+// user's average or best, it looks those up for testType and testValue, so
+// guests and users without a history for the test get no caret.
+func startPace(context *StateContext, base *TestBase, testType string, testValue int) {
+	user := context.model.session.User
+	config := user.Config
+
+	switch config.PaceCaret {
+	case paceTarget:
+		base.pace.wpm = float64(config.PaceWpm)
+	case paceAverage, paceBest:
+		if user.Id <= 0 {
+			return
+		}
+		filter := database.HistoryFilter{TestType: testType, TestValue: testValue}
+		average, best, err := database.GetPaceStats(context.model.context.UserRepository, user.Id, filter)
+		if err != nil {
+			return
+		}
+		if config.PaceCaret == paceAverage {
+			base.pace.wpm = average
+		} else {
+			base.pace.wpm = best
+		}
+	}
+}
+
+// movePace places the pace caret where typing at its speed would have got
+// to after elapsed, counting five characters to a word, or where the ghost
+// had got to. Test handlers call it from HandleInput so Render only draws.
+func (base *TestBase) movePace(elapsed time.Duration) {
+	if base.pace.ghost != nil {
+		base.pace.cursor = base.pace.ghost.advance(base.wordsToEnter, elapsed.Milliseconds())
+		return
+	}
+	base.pace.cursor = int(elapsed.Minutes() * base.pace.wpm * 5)
+}
+
+// paceAt reports whether the pace caret is drawn at position. It isn't drawn
+// over the cursor, on line breaks or past the end of the text.
+func (base *TestBase) paceAt(position int) bool {
+	return (base.pace.wpm > 0 || base.pace.ghost != nil) && position == base.pace.cursor &&
+		position != len(base.inputBuffer) && position < len(base.wordsToEnter) &&
+		base.wordsToEnter[position] != '\n'
+}
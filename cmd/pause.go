@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"time"
+
+	"charm.land/bubbles/v2/timer"
+	tea "charm.land/bubbletea/v2"
+)
+
+const pauseKey = "ctrl+p"
+
+// pauseClock keeps track of the time a test spent paused so it can be left
+// out of the test's elapsed time.
+type pauseClock struct {
+	paused      bool
+	pausedAt    time.Time
+	pausedTotal time.Duration
+}
+
+func (p *pauseClock) pause() {
+	p.paused = true
+	p.pausedAt = time.Now()
+}
+
+func (p *pauseClock) resume() {
+	p.pausedTotal += time.Since(p.pausedAt)
+	p.paused = false
+}
+
+// pausedFor is the time spent paused so far, counting a pause still going on.
+func (p *pauseClock) pausedFor() time.Duration {
+	if p.paused {
+		return p.pausedTotal + time.Since(p.pausedAt)
+	}
+	return p.pausedTotal
+}
+
+func (p *pauseClock) Paused() bool {
+	return p.paused
+}
+
+// pausable is the clock of a test that can be paused.
+type pausable interface {
+	Pause() tea.Cmd
+	Resume() tea.Cmd
+	Paused() bool
+}
+
+// Pause stops the countdown. Ticks that arrive while paused must be dropped by
+// the test, which ends the tick loop until Resume starts a new one.
+func (t *Timer) Pause() tea.Cmd {
+	if !t.isRunning || t.timedout || t.paused {
+		return nil
+	}
+	t.pause()
+	return nil
+}
+
+// Resume continues the countdown from where it was paused. The timer is
+// replaced so ticks still in flight from before the pause are ignored.
+func (t *Timer) Resume() tea.Cmd {
+	if !t.paused {
+		return nil
+	}
+	t.resume()
+	t.timer = timer.New(t.timer.Timeout)
+	return t.timer.Init()
+}
+
+func (sw *StopWatch) Pause() tea.Cmd {
+	if !sw.isRunning || sw.paused {
+		return nil
+	}
+	sw.pause()
+	return sw.stopwatch.Stop()
+}
+
+func (sw *StopWatch) Resume() tea.Cmd {
+	if !sw.paused {
+		return nil
+	}
+	sw.resume()
+	return sw.stopwatch.Start()
+}
+
+// handlePause pauses a running test when the terminal loses focus or the
+// pause key is pressed, and resumes it when focus comes back or any key is
+// pressed. Leaving or restarting the test still works while paused. It
+// reports whether msg was taken up and shouldn't reach the test.
+func handlePause(msg tea.Msg, clock pausable) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tea.BlurMsg:
+		return clock.Pause(), true
+	case tea.FocusMsg:
+		return clock.Resume(), true
+	case tea.KeyPressMsg:
+		if clock.Paused() {
+			switch msg.String() {
+			case "esc", "ctrl+q", "ctrl+w", "ctrl+r":
+				return nil, false
+			}
+			return clock.Resume(), true
+		}
+		if msg.String() == pauseKey {
+			return clock.Pause(), true
+		}
+	}
+	return nil, false
+}
+
+// pausedLabel is shown next to the clock of a paused test.
+func pausedLabel(clock pausable, styles Styles) string {
+	if !clock.Paused() {
+		return ""
+	}
+	return "  " + style("paused, press any key to resume", styles.themeFunc)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/timer"
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestStopWatchElapsedExcludesPause(t *testing.T) {
+	sw := &StopWatch{
+		isRunning: true,
+		startTime: time.Now().Add(-2 * time.Second),
+	}
+
+	sw.Pause()
+	if !sw.Paused() {
+		t.Fatal("expected the stopwatch to be paused")
+	}
+	frozen := sw.Elapsed()
+	time.Sleep(200 * time.Millisecond)
+	if diff := sw.Elapsed() - frozen; diff > 20*time.Millisecond {
+		t.Errorf("expected elapsed time to stand still while paused, it moved by %v", diff)
+	}
+
+	sw.Resume()
+	elapsed := sw.Elapsed()
+	if elapsed < 2*time.Second || elapsed > 2*time.Second+50*time.Millisecond {
+		t.Errorf("expected about 2s elapsed after resuming, got %v", elapsed)
+	}
+}
+
+func TestTimerPauseAndResume(t *testing.T) {
+	tm := &Timer{
+		timer:     timer.New(30 * time.Second),
+		duration:  30 * time.Second,
+		isRunning: true,
+		startTime: time.Now().Add(-time.Second),
+	}
+	tm.timer.Timeout = 29 * time.Second
+	oldID := tm.timer.ID()
+
+	tm.Pause()
+	time.Sleep(100 * time.Millisecond)
+	if cmd := tm.Resume(); cmd == nil {
+		t.Fatal("expected resuming to restart the countdown")
+	}
+
+	if tm.timer.ID() == oldID {
+		t.Error("expected a new timer so ticks from before the pause are ignored")
+	}
+	if tm.timer.Timeout != 29*time.Second {
+		t.Errorf("expected the countdown to carry on from 29s, got %v", tm.timer.Timeout)
+	}
+	if elapsed := tm.Elapsed(); elapsed > time.Second+50*time.Millisecond {
+		t.Errorf("expected the pause to be left out of elapsed time, got %v", elapsed)
+	}
+}
+
+func TestPauseNotRunning(t *testing.T) {
+	sw := &StopWatch{}
+	if cmd := sw.Pause(); cmd != nil || sw.Paused() {
+		t.Error("expected a test that hasn't started not to pause")
+	}
+}
+
+func TestHandlePause(t *testing.T) {
+	sw := &StopWatch{isRunning: true, startTime: time.Now()}
+
+	if _, handled := handlePause(tea.BlurMsg{}, sw); !handled || !sw.Paused() {
+		t.Fatal("expected losing focus to pause the test")
+	}
+	if _, handled := handlePause(tea.KeyPressMsg{Code: tea.KeyEscape}, sw); handled {
+		t.Error("expected esc to still leave a paused test")
+	}
+	if _, handled := handlePause(tea.KeyPressMsg{Code: 'a', Text: "a"}, sw); !handled || sw.Paused() {
+		t.Error("expected a key press to resume the test without being typed")
+	}
+
+	if _, handled := handlePause(tea.KeyPressMsg{Code: 'p', Mod: tea.ModCtrl}, sw); !handled || !sw.Paused() {
+		t.Fatal("expected ctrl+p to pause the test")
+	}
+	if _, handled := handlePause(tea.FocusMsg{}, sw); !handled || sw.Paused() {
+		t.Error("expected regaining focus to resume the test")
+	}
+
+	if _, handled := handlePause(tea.KeyPressMsg{Code: 'a', Text: "a"}, sw); handled {
+		t.Error("expected key presses to reach a running test")
+	}
+}
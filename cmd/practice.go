@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"sort"
+	"unicode"
+
+	"termtyper/database"
+)
+
+const (
+	// practiceRecentTests is how many of a user's latest tests feed practice mode.
+	practiceRecentTests = 50
+	// practiceMinSamples keeps a single slip on a rare pair from dominating.
+	practiceMinSamples = 3
+	practiceBigrams    = 8
+)
+
+// collectBigramStats replays a test's key presses and records, for every pair
+// of adjacent letters, how long the second key took after the first and
+// whether it was mistyped. Pairs typed right after a backspace are skipped
+// since their timing says nothing about the transition.
+func collectBigramStats(base TestBase) []database.BigramStat {
+	stats := make(map[string]*database.BigramStat)
+	walkKeyRecord(base, func(typed typedKey) {
+		if !typed.timed || typed.position == 0 {
+			return
+		}
+		first := unicode.ToLower(base.wordsToEnter[typed.position-1])
+		second := unicode.ToLower(typed.expected)
+		if !unicode.IsLetter(first) || !unicode.IsLetter(second) {
+			return
+		}
+		bigram := string([]rune{first, second})
+		stat, ok := stats[bigram]
+		if !ok {
+			stat = &database.BigramStat{Bigram: bigram}
+			stats[bigram] = stat
+		}
+		stat.Occurrences++
+		stat.TotalMs += typed.sinceLast
+		if typed.press.key != typed.expected {
+			stat.Mistakes++
+		}
+	})
+
+	result := make([]database.BigramStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Bigram < result[j].Bigram })
+
+	return result
+}
+
+// bigramWeakness scores a bigram by its average latency, inflated by how often
+// it is mistyped, so a pair that is slow and error-prone ranks worst.
+func bigramWeakness(stat database.BigramStat) float64 {
+	averageMs := float64(stat.TotalMs) / float64(stat.Occurrences)
+	errorRate := float64(stat.Mistakes) / float64(stat.Occurrences)
+	return averageMs * (1 + 4*errorRate)
+}
+
+// weakestBigrams returns up to n bigrams, weakest first.
+func weakestBigrams(stats []database.BigramStat, n int) []string {
+	var candidates []database.BigramStat
+	for _, stat := range stats {
+		if stat.Occurrences >= practiceMinSamples {
+			candidates = append(candidates, stat)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return bigramWeakness(candidates[i]) > bigramWeakness(candidates[j])
+	})
+
+	var bigrams []string
+	for _, stat := range candidates[:min(n, len(candidates))] {
+		bigrams = append(bigrams, stat.Bigram)
+	}
+	return bigrams
+}
+
+// practiceStats loads the stats practice mode works from: the user's recent
+// tests when logged in, or what this session has typed so far for guests.
+func practiceStats(m *model) []database.BigramStat {
+	user := m.session.User
+	if user.Id > 0 {
+		stats, err := database.GetBigramStats(m.context.UserRepository, user.Id, practiceRecentTests)
+		if err == nil {
+			return stats
+		}
+	}
+
+	stats := make([]database.BigramStat, 0, len(m.session.BigramStats))
+	for _, stat := range m.session.BigramStats {
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// saveBigramStats stores a finished test's bigram stats against its history
+// record, or folds them into the session when there is no record to attach to.
+// Void tests are left out.
+func saveBigramStats(context *StateContext, testID int64, base TestBase) {
+	if base.integrity.void {
+		return
+	}
+
+	stats := collectBigramStats(base)
+	if len(stats) == 0 {
+		return
+	}
+
+	userID := context.model.session.User.Id
+	if userID > 0 && testID > 0 {
+		_ = database.SaveBigramStats(context.model.context.UserRepository, userID, testID, stats)
+		return
+	}
+
+	session := context.model.session
+	if session.BigramStats == nil {
+		session.BigramStats = make(map[string]database.BigramStat)
+	}
+	for _, stat := range stats {
+		total := session.BigramStats[stat.Bigram]
+		total.Bigram = stat.Bigram
+		total.Occurrences += stat.Occurrences
+		total.Mistakes += stat.Mistakes
+		total.TotalMs += stat.TotalMs
+		session.BigramStats[stat.Bigram] = total
+	}
+}
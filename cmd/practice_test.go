@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/database"
+)
+
+func TestCollectBigramStats(t *testing.T) {
+	base := newTestBase("the ox")
+	base.testRecord = []KeyPress{
+		{key: 't', timestamp: 0},
+		{key: 'h', timestamp: 100},
+		{key: 'x', timestamp: 400},
+		{key: '\b', timestamp: 500},
+		{key: 'e', timestamp: 600},
+		{key: ' ', timestamp: 700},
+		{key: 'o', timestamp: 800},
+		{key: 'x', timestamp: 850},
+	}
+
+	stats := make(map[string]database.BigramStat)
+	for _, stat := range collectBigramStats(base) {
+		stats[stat.Bigram] = stat
+	}
+
+	if len(stats) != 3 {
+		t.Fatalf("expected th, he and ox, got %v", stats)
+	}
+	if th := stats["th"]; th.Occurrences != 1 || th.Mistakes != 0 || th.TotalMs != 100 {
+		t.Errorf("unexpected th stats: %+v", th)
+	}
+	// The correction after the backspace isn't timed, only the mistyped attempt.
+	if he := stats["he"]; he.Occurrences != 1 || he.Mistakes != 1 || he.TotalMs != 300 {
+		t.Errorf("unexpected he stats: %+v", he)
+	}
+	if ox := stats["ox"]; ox.Occurrences != 1 || ox.TotalMs != 50 {
+		t.Errorf("unexpected ox stats: %+v", ox)
+	}
+}
+
+func TestWeakestBigrams(t *testing.T) {
+	stats := []database.BigramStat{
+		{Bigram: "th", Occurrences: 50, Mistakes: 0, TotalMs: 5000},
+		{Bigram: "qu", Occurrences: 10, Mistakes: 0, TotalMs: 3000},
+		{Bigram: "ck", Occurrences: 10, Mistakes: 5, TotalMs: 1500},
+		{Bigram: "zz", Occurrences: 1, Mistakes: 1, TotalMs: 900},
+	}
+
+	got := weakestBigrams(stats, 2)
+	if len(got) != 2 || got[0] != "ck" || got[1] != "qu" {
+		t.Errorf("expected [ck qu], got %v", got)
+	}
+
+	if got := weakestBigrams(nil, 3); len(got) != 0 {
+		t.Errorf("expected no bigrams without stats, got %v", got)
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+
+	"termtyper/words"
+)
+
+type PracticeTestHandler struct {
+	*stopwatchTest
+	bigrams []string
+}
+
+// NewPracticeTestHandler builds a test from words containing the user's
+// weakest bigrams. Without enough stats yet it is a regular word count test.
+func NewPracticeTestHandler(menu MainMenuHandler, m *model) *PracticeTestHandler {
+	bigrams := weakestBigrams(practiceStats(m), practiceBigrams)
+
+	menu.wordTestWordGenerator.Count = menu.currentUser.Config.Words
+	menu.wordTestWordGenerator.Punctuation = false
+	menu.wordTestWordGenerator.Numbers = false
+	menu.wordTestWordGenerator.Symbols = false
+	menu.wordTestWordGenerator.KeyFilter = words.KeyFilter{}
+	list := words.ResolveList(menu.currentUser.Config.WordList, menu.currentUser.Config.Language)
+
+	h := &PracticeTestHandler{bigrams: bigrams}
+	h.stopwatchTest = newStopwatchTest(StatePracticeTest, menu, menu.wordTestWordGenerator.GeneratePractice(list, bigrams), h)
+	return h
+}
+
+func (h *PracticeTestHandler) testType() string {
+	return "practice"
+}
+
+func (h *PracticeTestHandler) testValue() int {
+	return h.base.mainMenu.wordTestWordGenerator.Count
+}
+
+func (h *PracticeTestHandler) label() string {
+	if len(h.bigrams) > 0 {
+		return "practising " + strings.Join(h.bigrams, " ")
+	}
+	return "not enough stats yet"
+}
+
+func (h *PracticeTestHandler) restart(context *StateContext) StateHandler {
+	return NewPracticeTestHandler(h.base.mainMenu, context.model)
+}
+
+func (h *PracticeTestHandler) finish(context *StateContext, testID int64, results *ResultsHandler) {}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"termtyper/database"
+	"termtyper/words"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// separatorWeights is how many of words.PunctuationWeightNames are separators;
+// the rest are shown in a second group.
+const separatorWeights = 9
+
+type PunctuationWeightsHandler struct {
+	*BaseStateHandler
+	form    *huh.Form
+	percent map[string]*string
+	err     error
+}
+
+// NewPunctuationWeightsHandler edits the custom punctuation profile as
+// percentages, starting from the saved weights or the normal profile.
+func NewPunctuationWeightsHandler(config *database.UserConfig) *PunctuationWeightsHandler {
+	weights := config.PunctuationWeights
+	if len(weights) == 0 {
+		weights = words.PunctuationProfiles["normal"].Weights()
+	}
+
+	percent := make(map[string]*string, len(words.PunctuationWeightNames))
+	var separators, others []huh.Field
+	for i, name := range words.PunctuationWeightNames {
+		value := strconv.FormatFloat(weights[name]*100, 'f', -1, 64)
+		percent[name] = &value
+
+		input := huh.NewInput().
+			Title(strings.ReplaceAll(name, "_", " ") + " %").
+			Value(percent[name]).
+			Validate(validatePercent)
+		if i < separatorWeights {
+			separators = append(separators, input)
+		} else {
+			others = append(others, input)
+		}
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(separators...).Title("Chance of each separator after a word"),
+		huh.NewGroup(others...).Title("Quotes and words"),
+	)
+
+	return &PunctuationWeightsHandler{
+		BaseStateHandler: NewBaseStateHandler(StatePunctuationWeights),
+		form:             form,
+		percent:          percent,
+	}
+}
+
+func validatePercent(str string) error {
+	value, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil || value < 0 || value > 100 {
+		return fmt.Errorf("enter a percentage between 0 and 100")
+	}
+	return nil
+}
+
+func (h *PunctuationWeightsHandler) weights() map[string]float64 {
+	weights := make(map[string]float64, len(h.percent))
+	for name, value := range h.percent {
+		percent, _ := strconv.ParseFloat(strings.TrimSpace(*value), 64)
+		weights[name] = percent / 100
+	}
+	return weights
+}
+
+func (h *PunctuationWeightsHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+q":
+			if h.ValidateTransition(StateSettings, context) {
+				return NewSettingsHandler(context.model.session.User), nil
+			}
+		}
+	}
+
+	var commands []tea.Cmd
+	updatedForm, formCmd := h.form.Update(msg)
+	if f, ok := updatedForm.(*huh.Form); ok {
+		h.form = f
+		commands = append(commands, formCmd)
+	}
+
+	if h.form.State == huh.StateCompleted {
+		weights := h.weights()
+		if _, err := words.CustomPunctuationProfile(weights); err != nil {
+			retry := NewPunctuationWeightsHandler(&database.UserConfig{PunctuationWeights: weights})
+			retry.err = err
+			return retry, nil
+		}
+
+		newUserConfig := context.model.session.User.Config
+		newUserConfig.PunctuationProfile = words.CustomPunctuation
+		newUserConfig.PunctuationWeights = weights
+
+		database.UpdateUserConfigStandalone(
+			context.model.context.UserRepository,
+			context.model.session.User.Id,
+			UserConfigToMap(newUserConfig))
+
+		if h.ValidateTransition(StateSettings, context) {
+			return NewSettingsHandler(context.model.session.User), nil
+		}
+	}
+
+	return h, tea.Batch(commands...)
+}
+
+func (h *PunctuationWeightsHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+
+	title := style("Custom Punctuation", m.styles.themeFunc)
+	title = lipgloss.NewStyle().PaddingBottom(1).Render(title)
+
+	sections := []string{title, h.form.View()}
+	if h.err != nil {
+		sections = append(sections, style(h.err.Error(), m.styles.mistake))
+	}
+	helpText := lipgloss.NewStyle().Faint(true).Render("enter: next • esc/ctrl+q: back without saving")
+	sections = append(sections, "", helpText)
+
+	joined := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	s := lipgloss.NewStyle().Align(lipgloss.Left).Render(joined)
+	centeredText := lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, s)
+
+	return centeredText
+}
+
+func (h *PunctuationWeightsHandler) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"termtyper/words"
+)
+
+type QuoteTestHandler struct {
+	*stopwatchTest
+	quote words.Quote
+}
+
+func NewQuoteTestHandler(menu MainMenuHandler) *QuoteTestHandler {
+	quoteLength := words.ParseQuoteLength(menu.currentUser.Config.QuoteLength)
+	quote := menu.textGenerator.GenerateQuote("English quotes", quoteLength)
+	h := &QuoteTestHandler{quote: quote}
+	h.stopwatchTest = newStopwatchTest(StateQuoteTest, menu, []rune(quote.Text), h)
+	return h
+}
+
+func (h *QuoteTestHandler) testType() string {
+	return "quote"
+}
+
+func (h *QuoteTestHandler) testValue() int {
+	return h.quote.Id
+}
+
+func (h *QuoteTestHandler) label() string {
+	return ""
+}
+
+func (h *QuoteTestHandler) restart(context *StateContext) StateHandler {
+	return NewQuoteTestHandler(h.base.mainMenu)
+}
+
+func (h *QuoteTestHandler) finish(context *StateContext, testID int64, results *ResultsHandler) {
+	quote := h.quote
+	results.quote = &quote
+}
@@ -25,6 +25,8 @@ type ReplayHandler struct {
 func NewReplayHandler(results ResultsHandler) *ReplayHandler {
 	results.test.inputBuffer = make([]rune, 0)
 	results.test.cursor = 0
+	results.test.mistakes = mistakes{mistakesAt: make(map[int]bool)}
+	results.test.extra = nil
 	return &ReplayHandler{
 		BaseStateHandler:  NewBaseStateHandler(StateReplay),
 		test:              results.test,
@@ -57,6 +59,16 @@ func (h *ReplayHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHa
 						return NewTimerTestHandler(h.results.mainMenu), nil
 					case "wordcount":
 						return NewWordCountTestHandler(h.results.mainMenu), nil
+					case "quote":
+						return NewQuoteTestHandler(h.results.mainMenu), nil
+					case "code":
+						return NewCodeTestHandler(h.results.mainMenu), nil
+					case "practice":
+						return NewPracticeTestHandler(h.results.mainMenu, context.model), nil
+					case "book":
+						return NewBookTestHandler(h.results.mainMenu, context.model, *h.results.book), nil
+					case "mistakes":
+						return NewMistakesTestHandler(h.results.mainMenu, recentMissedWords(context.model)), nil
 					}
 
 				case "Main Menu":
@@ -81,6 +93,7 @@ func (h *ReplayHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHa
 		return h, nil
 	}
 
+	h.test.movePace(h.stopwatch.Elapsed())
 	switch msg := msg.(type) {
 	case stopwatch.StartStopMsg:
 		stopwatchUpdate, cmdUpdate := h.stopwatch.stopwatch.Update(msg)
@@ -137,7 +150,7 @@ func (h *ReplayHandler) Render(m *model) string {
 	stopwatch := style(stopwatchViewSeconds, m.styles.themeFunc)
 	paragraphView := h.test.renderParagraph(lineLenLimit, m.styles)
 	lines := strings.Split(paragraphView, "\n")
-	cursorLine := findCursorLine(strings.Split(paragraphView, "\n"), h.test.cursor)
+	cursorLine := findCursorLine(strings.Split(paragraphView, "\n"), h.test.displayCursor())
 
 	linesAroundCursor := strings.Join(getLinesAroundCursor(lines, cursorLine), "\n")
 
@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"math"
-	"strings"
+	"termtyper/database"
+	"termtyper/words"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -19,6 +19,7 @@ type ResultsHandler struct {
 	rawWpm int
 	cpm    int
 	time   time.Duration
+	chars  characterStats
 	//wordList         string
 	test             TestBase
 	wpmEachSecond    []float64
@@ -26,6 +27,20 @@ type ResultsHandler struct {
 	resultsSelection []string
 	cursor           int
 	wpmChart         *WPMChartBubble
+	quote            *words.Quote
+	snippet          *words.CodeSnippet
+	seed             string
+	book             *words.Book
+	bookProgress     string
+	heatmap          *KeyHeatmap
+	// raceResult says how a race against a ghost went.
+	raceResult string
+	// retrySeed makes Next Test type the text of seed again, for tests
+	// started from a seed code.
+	retrySeed bool
+	// recentHeatmap is set while the heatmap shows the user's recent tests
+	// rather than this one.
+	recentHeatmap bool
 }
 
 func NewResultsHandler() *ResultsHandler {
@@ -44,17 +59,47 @@ func (h *ResultsHandler) HandleInput(msg tea.Msg, context *StateContext) (StateH
 		case "esc":
 			return NewMainMenuHandler(context.model.session.User, context.model), nil
 
+		case "tab":
+			user := context.model.session.User
+			if user.Id <= 0 {
+				break
+			}
+			if h.recentHeatmap {
+				h.heatmap = testHeatmap(h.test)
+				h.recentHeatmap = false
+			} else if stats, err := database.GetKeyStats(context.model.context.UserRepository, user.Id, heatmapRecentTests); err == nil {
+				h.heatmap = NewKeyHeatmap(fmt.Sprintf("Key Heatmap (last %d tests)", heatmapRecentTests), stats)
+				h.recentHeatmap = true
+			}
+
 		case "enter":
 			if h.resultsSelection[newCursor] == "Next Test" {
+				if seed, err := ParseSeedCode(h.seed); h.retrySeed && err == nil {
+					return NewSeededTestHandler(h.mainMenu, seed, nil), nil
+				}
 				if h.testType == "timer" {
 					return NewTimerTestHandler(h.mainMenu), nil
 				} else if h.testType == "wordcount" {
 					return NewWordCountTestHandler(h.mainMenu), nil
+				} else if h.testType == "quote" {
+					return NewQuoteTestHandler(h.mainMenu), nil
+				} else if h.testType == "code" {
+					return NewCodeTestHandler(h.mainMenu), nil
+				} else if h.testType == "practice" {
+					return NewPracticeTestHandler(h.mainMenu, context.model), nil
+				} else if h.testType == "book" {
+					return NewBookTestHandler(h.mainMenu, context.model, *h.book), nil
+				} else if h.testType == "mistakes" {
+					return NewMistakesTestHandler(h.mainMenu, recentMissedWords(context.model)), nil
 				}
 			} else if h.resultsSelection[newCursor] == "Main Menu" {
 				return NewMainMenuHandler(context.model.session.User, context.model), nil
 			} else if h.resultsSelection[newCursor] == "Replay" {
 				return NewReplayHandler(*h), nil
+			} else if h.resultsSelection[newCursor] == "Practice Mistakes" {
+				return NewMistakesTestHandler(h.mainMenu, missedWordList(collectMissedWords(h.test))), nil
+			} else if h.resultsSelection[newCursor] == "Race Ghost" {
+				return NewGhostRaceHandler(h.mainMenu, context.model, h.seed), nil
 			}
 
 		case "left", "h":
@@ -80,19 +125,50 @@ func (h *ResultsHandler) Render(m *model) string {
 	termWidth, termHeight := m.width-2, m.height-2
 
 	title := style("Test Results", m.styles.themeFunc)
+	if h.test.failed != "" {
+		title = style("Test Failed", m.styles.mistake)
+	}
 	title = lipgloss.NewStyle().PaddingBottom(1).Render(title)
 
 	var content []string
+	if h.test.failed != "" {
+		reason := style(h.test.failed, m.styles.mistake)
+		content = append(content, lipgloss.NewStyle().PaddingBottom(1).Render(reason))
+	}
 	content = append(content, fmt.Sprintf("WPM: %d", h.wpm))
 	content = append(content, fmt.Sprintf("Accuracy: %.1f%%", h.accuracy))
+	if h.quote != nil {
+		attribution := style("— "+h.quote.Attribution(), m.styles.toEnter)
+		content = append(content, lipgloss.NewStyle().PaddingTop(1).Render(attribution))
+	}
+	if h.snippet != nil {
+		language := style(h.snippet.Language+" snippet", m.styles.toEnter)
+		content = append(content, lipgloss.NewStyle().PaddingTop(1).Render(language))
+	}
+	if h.book != nil {
+		progress := style(h.bookProgress, m.styles.toEnter)
+		content = append(content, lipgloss.NewStyle().PaddingTop(1).Render(progress))
+	}
+	if h.seed != "" {
+		seed := style("seed "+h.seed, m.styles.toEnter)
+		content = append(content, lipgloss.NewStyle().PaddingTop(1).Render(seed))
+	}
+	if h.raceResult != "" {
+		race := style(h.raceResult, m.styles.themeFunc)
+		content = append(content, lipgloss.NewStyle().PaddingTop(1).Render(race))
+	}
+	if integrity := h.test.integrity; integrity.reason != "" {
+		verdict := "Flagged: "
+		if integrity.void {
+			verdict = "Not saved: "
+		}
+		warning := style(verdict+integrity.reason, m.styles.mistake)
+		content = append(content, lipgloss.NewStyle().PaddingTop(1).Render(warning))
+	}
 
-	// if h.results.testType == "timer" {
-	// 	content = append(content, fmt.Sprintf("Time: %s", formatDuration(h.results.duration)))
-	// 	content = append(content, fmt.Sprintf("Words: %d", h.results.wordsTyped))
-	// } else if h.results.testType == "wordcount" {
-	// 	content = append(content, fmt.Sprintf("Words: %d/%d", h.results.wordsTyped, h.results.targetWords))
-	// 	content = append(content, fmt.Sprintf("Time: %s", formatDuration(h.results.duration)))
-	// }
+	content = append(content, fmt.Sprintf("Raw: %d  CPM: %d  Time: %s", h.rawWpm, h.cpm, formatDuration(h.time)))
+	content = append(content, fmt.Sprintf("Characters: %d/%d/%d/%d", h.chars.correct, h.chars.incorrect, h.chars.extra, h.chars.missed))
+	content = append(content, style("correct/incorrect/extra/missed", m.styles.toEnter))
 
 	var menuItems []string
 	menuItemsStyle := lipgloss.NewStyle().Padding(0, 0, 0, 0)
@@ -105,10 +181,20 @@ func (h *ResultsHandler) Render(m *model) string {
 
 	resultsMenu := lipgloss.JoinHorizontal(lipgloss.Center, menuItems...)
 
+	var heatmap string
+	if h.heatmap != nil {
+		heatmap = h.heatmap.View()
+		if m.session.User.Id > 0 {
+			heatmap += "\n" + lipgloss.NewStyle().Faint(true).Render("tab: this test / recent tests")
+		}
+	}
+
 	fullParagraph := lipgloss.JoinVertical(
 		lipgloss.Center, resultsStyle.Padding(0).Render(title),
 		menuItemsStyle.Padding(0).Render(content...),
 		h.wpmChart.View(),
+		heatmap,
+		"",
 		menuItemsStyle.Render(resultsMenu),
 	)
 	s := lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, fullParagraph)
@@ -133,30 +219,40 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%.1fs", d.Seconds())
 }
 
+// calculateRawWpm counts everything left typed, right or wrong, including the
+// separators between words.
 func (base TestBase) calculateRawWpm(elapsedMinutes float64) float64 {
-	return base.calculateWpm(len(strings.Split(string(base.inputBuffer), " ")), elapsedMinutes)
+	chars := base.characterStats()
+	return calculateWpm(chars.correct+chars.incorrect+chars.extra+chars.spaces, elapsedMinutes)
 }
 
-func (base TestBase) calculateWpm(characterCount int, elapsedMinutes float64) float64 {
+func calculateWpm(characterCount int, elapsedMinutes float64) float64 {
 	if elapsedMinutes == 0 {
 		return 0
-	} else {
-		grossWpm := float64(characterCount) / elapsedMinutes
-		netWpm := grossWpm - float64(len(base.mistakes.mistakesAt))/elapsedMinutes
-
-		return math.Max(0, netWpm)
 	}
+	return float64(characterCount) / 5 / elapsedMinutes
 }
 
+// calculateNormalizedWpm counts only the words typed without errors and the
+// spaces after them, in words of five characters.
 func (base TestBase) calculateNormalizedWpm(elapsedMinutes float64) float64 {
-	return base.calculateWpm(len(base.inputBuffer)/5, elapsedMinutes)
+	chars := base.characterStats()
+	return calculateWpm(chars.correctWordChars+chars.correctSpaces, elapsedMinutes)
 }
 
 func (base TestBase) calculateCpm(elapsedMinutes float64) int {
+	if elapsedMinutes == 0 {
+		return 0
+	}
 	return int(float64(base.rawInputCount) / elapsedMinutes)
 }
 
+// calculateAccuracy is the share of key presses that were right, corrected
+// mistakes included.
 func (base TestBase) calculateAccuracy() float64 {
+	if base.rawInputCount == 0 {
+		return 0
+	}
 	mistakesRate := float64(base.mistakes.rawMistakesCnt*100) / float64(base.rawInputCount)
 	accuracy := 100 - mistakesRate
 	return accuracy
@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"termtyper/database"
+	"termtyper/words"
+)
+
+// SeedCode holds everything needed to regenerate a test's text: the mode and
+// its length, the generator settings and the seed itself. Its string form is
+// short enough to paste into a chat, e.g. "t30p-en-1Z4K9Q".
+type SeedCode struct {
+	TestType    string
+	Value       int
+	Punctuation bool
+	// Profile names the punctuation profile; it is empty without punctuation.
+	Profile string
+	// Weights are the custom profile's weights, carried in the code so that
+	// anyone can regenerate the text.
+	Weights   words.PunctuationProfile
+	Numbers   bool
+	Symbols   bool
+	Frequency words.Frequency
+	KeyFilter words.KeyFilter
+	List      string
+	Seed      uint64
+}
+
+var seedCodePattern = regexp.MustCompile(`^([tw])(\d+)(p(?:[lh]|c[0-9a-z]{24})?)?(n?)(s?)(?:f(\d))?(?:k([hlrt]|c[^-]+))?-(.+)-([0-9a-zA-Z]+)$`)
+
+// seedFrequencyDigits are the digits after "f" for frequency tiers other than
+// all. 2 and 3 were the top1k and top10k tiers, which drew from the whole of
+// every shipped list, so codes with them are read as all.
+var seedFrequencyDigits = map[words.Frequency]int{
+	words.FrequencyTop200: 1,
+	words.FrequencyRare:   4,
+}
+
+func parseSeedFrequencyDigit(digit string) (words.Frequency, bool) {
+	switch digit {
+	case "2", "3":
+		return words.FrequencyAll, true
+	}
+	for frequency, frequencyDigit := range seedFrequencyDigits {
+		if digit == strconv.Itoa(frequencyDigit) {
+			return frequency, true
+		}
+	}
+	return words.FrequencyAll, false
+}
+
+// seedProfileCodes are the letters after "p" for profiles other than normal.
+// The custom profile's letter is followed by its weights.
+var seedProfileCodes = map[string]string{"light": "l", "heavy": "h", words.CustomPunctuation: "c"}
+
+// seedWeightScale is how finely a seed code carries custom punctuation
+// weights: in tenths of a percent, two base-36 digits each.
+const seedWeightScale = 1000
+
+// newSeedCode picks a fresh seed for a test using the user's current settings.
+func newSeedCode(testType string, config *database.UserConfig) SeedCode {
+	value := config.Time
+	if testType == "words" {
+		value = config.Words
+	}
+
+	profile := ""
+	var weights words.PunctuationProfile
+	if config.Punctuation {
+		profile = words.PunctuationProfileName(config.PunctuationProfile)
+	}
+	if profile == words.CustomPunctuation {
+		custom, err := words.CustomPunctuationProfile(config.PunctuationWeights)
+		if err != nil {
+			profile = "normal"
+		} else {
+			weights = quantizeWeights(custom)
+		}
+	}
+
+	return SeedCode{
+		TestType:    testType,
+		Value:       value,
+		Punctuation: config.Punctuation,
+		Profile:     profile,
+		Weights:     weights,
+		Numbers:     config.Numbers,
+		Symbols:     config.Symbols,
+		Frequency:   words.ParseFrequency(config.Frequency),
+		KeyFilter:   words.ParseKeyFilter(config.KeyFilter, config.KeyFilterKeys),
+		List:        words.ResolveList(config.WordList, config.Language),
+		Seed:        uint64(rand.Uint32()),
+	}
+}
+
+func (c SeedCode) String() string {
+	var b strings.Builder
+	if c.TestType == "words" {
+		b.WriteString("w")
+	} else {
+		b.WriteString("t")
+	}
+	b.WriteString(strconv.Itoa(c.Value))
+	if c.Punctuation {
+		b.WriteString("p" + seedProfileCodes[c.Profile])
+		if c.Profile == words.CustomPunctuation {
+			b.WriteString(seedWeightsCode(c.Weights))
+		}
+	}
+	if c.Numbers {
+		b.WriteString("n")
+	}
+	if c.Symbols {
+		b.WriteString("s")
+	}
+	if c.Frequency != words.FrequencyAll {
+		fmt.Fprintf(&b, "f%d", seedFrequencyDigits[c.Frequency])
+	}
+	if c.KeyFilter.Active() {
+		b.WriteString("k" + seedKeyFilterCode(c.KeyFilter))
+	}
+	b.WriteString("-" + seedListCode(c.List) + "-")
+	b.WriteString(strings.ToUpper(strconv.FormatUint(c.Seed, 36)))
+
+	return b.String()
+}
+
+// ParseSeedCode reads a code produced by SeedCode.String. Codes that name a
+// word list this machine doesn't have are rejected.
+func ParseSeedCode(code string) (SeedCode, error) {
+	match := seedCodePattern.FindStringSubmatch(strings.TrimSpace(code))
+	if match == nil {
+		return SeedCode{}, fmt.Errorf("invalid seed code %q", code)
+	}
+
+	seed := SeedCode{TestType: "timer"}
+	if match[1] == "w" {
+		seed.TestType = "words"
+	}
+
+	if match[3] != "" {
+		seed.Punctuation = true
+		seed.Profile = "normal"
+		for name, profileCode := range seedProfileCodes {
+			if match[3] == "p"+profileCode {
+				seed.Profile = name
+			}
+		}
+		if strings.HasPrefix(match[3], "pc") {
+			weights, err := parseSeedWeightsCode(match[3][2:])
+			if err != nil {
+				return SeedCode{}, fmt.Errorf("seed code %q: %w", code, err)
+			}
+			seed.Profile = words.CustomPunctuation
+			seed.Weights = weights
+		}
+	}
+
+	seed.Numbers = match[4] == "n"
+	seed.Symbols = match[5] == "s"
+
+	value, err := strconv.Atoi(match[2])
+	if err != nil {
+		return SeedCode{}, fmt.Errorf("invalid seed code %q: %w", code, err)
+	}
+	maxValue := 1440
+	if seed.TestType == "words" {
+		maxValue = 500
+	}
+	if value < 1 || value > maxValue {
+		return SeedCode{}, fmt.Errorf("seed code %q is out of range", code)
+	}
+	seed.Value = value
+
+	if match[6] != "" {
+		frequency, ok := parseSeedFrequencyDigit(match[6])
+		if !ok {
+			return SeedCode{}, fmt.Errorf("seed code %q has an unknown frequency tier", code)
+		}
+		seed.Frequency = frequency
+	}
+
+	if match[7] != "" {
+		seed.KeyFilter = parseSeedKeyFilterCode(match[7])
+		if !seed.KeyFilter.Active() {
+			return SeedCode{}, fmt.Errorf("seed code %q has an empty key set", code)
+		}
+	}
+
+	list, err := parseSeedListCode(match[8])
+	if err != nil {
+		return SeedCode{}, err
+	}
+	seed.List = list
+
+	seed.Seed, err = strconv.ParseUint(strings.ToLower(match[9]), 36, 64)
+	if err != nil {
+		return SeedCode{}, fmt.Errorf("invalid seed code %q: %w", code, err)
+	}
+
+	return seed, nil
+}
+
+// seedKeyFilterCode is the first letter of a preset, or "c" followed by the
+// keys of a custom set.
+func seedKeyFilterCode(filter words.KeyFilter) string {
+	if filter.Name == words.CustomKeyFilter {
+		return "c" + filter.Keys
+	}
+	return filter.Name[:1]
+}
+
+func parseSeedKeyFilterCode(code string) words.KeyFilter {
+	if strings.HasPrefix(code, "c") {
+		return words.ParseKeyFilter(words.CustomKeyFilter, code[1:])
+	}
+	for _, name := range words.KeyFilterNames {
+		if name != words.CustomKeyFilter && name[:1] == code {
+			return words.ParseKeyFilter(name, "")
+		}
+	}
+	return words.KeyFilter{}
+}
+
+func seedListCode(list string) string {
+	for _, lang := range words.Languages {
+		if lang.List == list {
+			return lang.Code
+		}
+	}
+	return url.PathEscape(list)
+}
+
+func parseSeedListCode(code string) (string, error) {
+	for _, lang := range words.Languages {
+		if strings.EqualFold(lang.Code, code) {
+			return lang.List, nil
+		}
+	}
+
+	list, err := url.PathUnescape(code)
+	if err != nil {
+		return "", fmt.Errorf("invalid word list in seed code: %w", err)
+	}
+	if !slices.Contains(words.ListNames(), list) {
+		return "", fmt.Errorf("word list %q is not installed", list)
+	}
+	return list, nil
+}
+
+// quantizeWeights rounds a custom profile down to what a seed code carries,
+// so the text generated from the code is the text the test was typed on.
+// Rounding down keeps the separators from adding up to more than 1.
+func quantizeWeights(profile words.PunctuationProfile) words.PunctuationProfile {
+	weights := profile.Weights()
+	for name, weight := range weights {
+		weights[name] = math.Floor(weight*seedWeightScale+1e-9) / seedWeightScale
+	}
+	quantized, err := words.CustomPunctuationProfile(weights)
+	if err != nil {
+		return words.PunctuationProfile{}
+	}
+	return quantized
+}
+
+func seedWeightsCode(profile words.PunctuationProfile) string {
+	weights := profile.Weights()
+	var b strings.Builder
+	for _, name := range words.PunctuationWeightNames {
+		digits := strconv.FormatInt(int64(math.Round(weights[name]*seedWeightScale)), 36)
+		b.WriteString(strings.Repeat("0", 2-len(digits)) + digits)
+	}
+	return b.String()
+}
+
+func parseSeedWeightsCode(code string) (words.PunctuationProfile, error) {
+	weights := make(map[string]float64, len(words.PunctuationWeightNames))
+	for i, name := range words.PunctuationWeightNames {
+		value, err := strconv.ParseUint(code[i*2:i*2+2], 36, 16)
+		if err != nil || value > seedWeightScale {
+			return words.PunctuationProfile{}, fmt.Errorf("invalid punctuation weights")
+		}
+		weights[name] = float64(value) / seedWeightScale
+	}
+	return words.CustomPunctuationProfile(weights)
+}
+
+// punctuationProfile returns the weights of a seed's punctuation profile.
+func punctuationProfile(seed SeedCode) words.PunctuationProfile {
+	if seed.Profile == words.CustomPunctuation {
+		return seed.Weights
+	}
+	profile, err := words.ParsePunctuationProfile(seed.Profile, nil)
+	if err != nil {
+		return words.PunctuationProfiles["normal"]
+	}
+	return profile
+}
+
+// NewSeededTestHandler starts a test on the text of seed, with racer playing
+// alongside when it isn't nil.
+func NewSeededTestHandler(menu MainMenuHandler, seed SeedCode, racer *ghost) StateHandler {
+	if racer != nil {
+		racer.reset()
+	}
+
+	if seed.TestType == "words" {
+		h := NewSeededWordCountTestHandler(menu, seed)
+		h.base.pace.ghost = racer
+		return h
+	}
+	h := NewSeededTimerTestHandler(menu, seed)
+	h.base.pace.ghost = racer
+	return h
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"charm.land/huh/v2"
+	"charm.land/lipgloss/v2"
+)
+
+type SeedInputHandler struct {
+	*BaseStateHandler
+	form     *huh.Form
+	seedCode *string
+	menu     MainMenuHandler
+}
+
+func NewSeedInputHandler(menu MainMenuHandler) *SeedInputHandler {
+	seedCode := new(string)
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Seed code").
+				Placeholder("t30-en-1Z4K9Q").
+				Value(seedCode).
+				Validate(func(str string) error {
+					_, err := ParseSeedCode(str)
+					return err
+				}),
+		),
+	)
+
+	return &SeedInputHandler{
+		BaseStateHandler: NewBaseStateHandler(StateSeedInput),
+		form:             form,
+		seedCode:         seedCode,
+		menu:             menu,
+	}
+}
+
+func (h *SeedInputHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+q":
+			if h.ValidateTransition(StateMainMenu, context) {
+				return NewMainMenuHandler(context.model.session.User, context.model), nil
+			}
+		}
+	}
+
+	var commands []tea.Cmd
+	updatedForm, formCmd := h.form.Update(msg)
+	if f, ok := updatedForm.(*huh.Form); ok {
+		h.form = f
+		commands = append(commands, formCmd)
+	}
+
+	if h.form.State == huh.StateCompleted {
+		seed, err := ParseSeedCode(*h.seedCode)
+		if err != nil {
+			return NewSeedInputHandler(h.menu), nil
+		}
+
+		if seed.TestType == "words" {
+			if h.ValidateTransition(StateWordCountTest, context) {
+				return NewSeededWordCountTestHandler(h.menu, seed), nil
+			}
+		} else if h.ValidateTransition(StateTimerTest, context) {
+			return NewSeededTimerTestHandler(h.menu, seed), nil
+		}
+	}
+
+	return h, tea.Batch(commands...)
+}
+
+func (h *SeedInputHandler) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+
+	title := style("Type a Seed", m.styles.themeFunc)
+	title = lipgloss.NewStyle().PaddingBottom(1).Render(title)
+
+	helpText := lipgloss.NewStyle().Faint(true).Render("enter: start test • esc/ctrl+q: back")
+
+	joined := lipgloss.JoinVertical(lipgloss.Left, title, h.form.View(), "", helpText)
+	s := lipgloss.NewStyle().Align(lipgloss.Left).Render(joined)
+	centeredText := lipgloss.Place(termWidth, termHeight, lipgloss.Center, lipgloss.Center, s)
+
+	return centeredText
+}
+
+func (h *SeedInputHandler) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/database"
+	"termtyper/words"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestSeedCodeRoundTrip(t *testing.T) {
+	tests := []SeedCode{
+		{TestType: "timer", Value: 30, List: words.DefaultList, Seed: 123456},
+		{TestType: "words", Value: 45, Punctuation: true, Profile: "normal", List: "German", Seed: 0},
+		{TestType: "timer", Value: 15, Punctuation: true, Profile: "heavy", List: words.DefaultList, Seed: 7},
+		{TestType: "words", Value: 30, Punctuation: true, Profile: "light", Numbers: true, Symbols: true, Frequency: words.FrequencyRare, List: words.DefaultList, Seed: 99},
+		{TestType: "timer", Value: 60, Symbols: true, List: "Polish", Seed: 1},
+		{TestType: "timer", Value: 30, KeyFilter: words.ParseKeyFilter("home", ""), List: words.DefaultList, Seed: 2},
+		{TestType: "words", Value: 15, Frequency: words.FrequencyTop200, KeyFilter: words.ParseKeyFilter(words.CustomKeyFilter, "fjdk"), List: "German", Seed: 3},
+		{TestType: "timer", Value: 1440, Frequency: words.FrequencyRare, List: "Russian", Seed: 4294967295},
+	}
+
+	for _, want := range tests {
+		code := want.String()
+		got, err := ParseSeedCode(code)
+		if err != nil {
+			t.Errorf("ParseSeedCode(%q) failed: %v", code, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseSeedCode(%q) = %+v, expected %+v", code, got, want)
+		}
+	}
+
+	if code := (SeedCode{TestType: "timer", Value: 30, Punctuation: true, List: words.DefaultList, Seed: 35}).String(); code != "t30p-en-Z" {
+		t.Errorf("unexpected seed code %q", code)
+	}
+}
+
+func TestSeedCodeRoundTripEveryProfile(t *testing.T) {
+	for _, profile := range words.PunctuationProfileNames {
+		t.Run(profile, func(t *testing.T) {
+			config := database.DefaultConfig
+			config.Punctuation = true
+			config.PunctuationProfile = profile
+			config.PunctuationWeights = map[string]float64{"comma": 0.0755, "period": 0.333, "contraction": 1}
+
+			want := newSeedCode("timer", &config)
+			if want.Profile != profile {
+				t.Fatalf("expected profile %q, got %q", profile, want.Profile)
+			}
+			code := want.String()
+			got, err := ParseSeedCode(code)
+			if err != nil {
+				t.Fatalf("ParseSeedCode(%q) failed: %v", code, err)
+			}
+			if got != want {
+				t.Errorf("ParseSeedCode(%q) = %+v, expected %+v", code, got, want)
+			}
+		})
+	}
+
+	// The custom weights travel in the code, rounded down to tenths of a
+	// percent, rather than coming from whoever enters it.
+	config := database.DefaultConfig
+	config.Punctuation = true
+	config.PunctuationProfile = words.CustomPunctuation
+	config.PunctuationWeights = map[string]float64{"comma": 0.0755, "period": 0.333, "contraction": 1}
+	seed, err := ParseSeedCode(newSeedCode("words", &config).String())
+	if err != nil {
+		t.Fatalf("ParseSeedCode failed: %v", err)
+	}
+	profile := punctuationProfile(seed)
+	if profile.Comma != 0.075 || profile.Period != 0.333 || profile.Contraction != 1 || profile.Quote != 0 {
+		t.Errorf("unexpected custom weights %+v", profile)
+	}
+}
+
+func TestParseSeedCodeRejectsInvalidCodes(t *testing.T) {
+	codes := []string{
+		"",
+		"t30",
+		"x30-en-1",
+		"t0-en-1",
+		"w501-en-1",
+		"t30f9-en-1",
+		"t30-not-installed-1",
+		"t30-en-!!",
+		"t30pc-en-1",
+		"t30pc0000000000000000000000-en-1",
+		"t30pczz0000000000000000000000-en-1",
+		"t30pcrsrs00000000000000000000-en-1",
+		"t30px-en-1",
+		"t30sn-en-1",
+		"t30kx-en-1",
+		"t30kc1-en-1",
+	}
+
+	for _, code := range codes {
+		if _, err := ParseSeedCode(code); err == nil {
+			t.Errorf("expected ParseSeedCode(%q) to fail", code)
+		}
+	}
+}
+
+func TestParseSeedCodeReadsDroppedFrequencyTiers(t *testing.T) {
+	for code, want := range map[string]words.Frequency{
+		"w30f1-en-1": words.FrequencyTop200,
+		"w30f2-en-1": words.FrequencyAll,
+		"w30f3-en-1": words.FrequencyAll,
+		"w30f4-en-1": words.FrequencyRare,
+	} {
+		seed, err := ParseSeedCode(code)
+		if err != nil {
+			t.Errorf("ParseSeedCode(%q) failed: %v", code, err)
+			continue
+		}
+		if seed.Frequency != want {
+			t.Errorf("ParseSeedCode(%q) frequency = %s, expected %s", code, seed.Frequency, want)
+		}
+	}
+}
+
+func TestSeededTestsShareText(t *testing.T) {
+	menu := MainMenuHandler{
+		timerTestWordGenerator: words.NewGenerator(),
+		wordTestWordGenerator:  words.NewGenerator(),
+		currentUser:            &database.ApplicationUser{Config: &database.UserConfig{Time: 30, Words: 30}},
+	}
+
+	seed, err := ParseSeedCode("t60p-fr-3J9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewSeededTimerTestHandler(menu, seed)
+	second := NewSeededTimerTestHandler(menu, seed)
+	if string(first.base.wordsToEnter) != string(second.base.wordsToEnter) {
+		t.Error("the same seed code should produce the same timer text")
+	}
+	if first.timer.duration.Seconds() != 60 {
+		t.Errorf("expected the seed code to set a 60s timer, got %v", first.timer.duration)
+	}
+
+	seed, err = ParseSeedCode("w15-en-ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewSeededWordCountTestHandler(menu, seed)
+	b := NewSeededWordCountTestHandler(menu, seed)
+	if string(a.base.wordsToEnter) != string(b.base.wordsToEnter) {
+		t.Error("the same seed code should produce the same word count text")
+	}
+}
+
+func TestSeededTestsRestartOnTheSameText(t *testing.T) {
+	m := newGuestModel()
+	context := &StateContext{model: m}
+	menu := MainMenuHandler{
+		timerTestWordGenerator: words.NewGenerator(),
+		wordTestWordGenerator:  words.NewGenerator(),
+		currentUser:            m.session.User,
+	}
+	restart := tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl}
+
+	for _, code := range []string{"t30-en-7", "w15-en-7"} {
+		seed, err := ParseSeedCode(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := NewSeededTestHandler(menu, seed, nil)
+		again, _ := h.HandleInput(restart, context)
+		if seedOf(again) != code {
+			t.Errorf("expected ctrl+r to restart %s on the same seed, got %q", code, seedOf(again))
+		}
+	}
+
+	h := NewWordCountTestHandler(menu)
+	again, _ := h.HandleInput(restart, context)
+	if seedOf(again) == h.seed.String() {
+		t.Error("expected ctrl+r to start a random test on a new seed")
+	}
+}
+
+func seedOf(handler StateHandler) string {
+	switch h := handler.(type) {
+	case *TimerTestHandler:
+		return h.seed.String()
+	case *WordCountTestHandler:
+		return h.seed.String()
+	}
+	return ""
+}
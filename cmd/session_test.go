@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"termtyper/database"
+)
+
+func newGuestModel() *model {
+	return &model{session: &Session{User: &database.ApplicationUser{Id: -1, Config: &database.DefaultConfig}}}
+}
+
+// underSessionLock runs f the way handlers run, inside model.Update's hold on
+// the session lock, and fails if f tries to take the lock again.
+func underSessionLock(t *testing.T, m *model, f func()) {
+	t.Helper()
+	m.session.mu.Lock()
+	defer m.session.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlocked on the session lock")
+	}
+}
+
+func TestGuestMissedWordsUnderSessionLock(t *testing.T) {
+	m := newGuestModel()
+	context := &StateContext{model: m}
+	base := newTestBase("the fox")
+	for _, key := range "thx fox" {
+		base.testRecord = append(base.testRecord, KeyPress{key: key})
+	}
+
+	var missed []string
+	underSessionLock(t, m, func() {
+		saveMissedWords(context, 0, base)
+		missed = recentMissedWords(m)
+	})
+	if len(missed) != 1 || missed[0] != "the" {
+		t.Errorf("expected the missed word to be kept for the guest, got %v", missed)
+	}
+}
+
+func TestGuestBigramStatsUnderSessionLock(t *testing.T) {
+	m := newGuestModel()
+	context := &StateContext{model: m}
+	base := newTestBase("the fox")
+	for i, key := range "the fox" {
+		base.testRecord = append(base.testRecord, KeyPress{key: key, timestamp: int64(i) * 100})
+	}
+
+	var stats []database.BigramStat
+	underSessionLock(t, m, func() {
+		saveBigramStats(context, 0, base)
+		stats = practiceStats(m)
+	})
+	if len(stats) == 0 {
+		t.Error("expected the guest's bigram stats to be kept")
+	}
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"termtyper/database"
+	"termtyper/words"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -24,16 +27,20 @@ type TestSetting interface {
 	SaveSettings(context *StateContext)
 }
 
+// TimerSettings and WordsSettings offer their presets followed by a custom
+// entry, at index len(selection), whose value is entered separately.
 type TimerSettings struct {
 	timerCursor     int
 	timerSelection  []int
 	selectionCursor int
+	custom          int
 }
 
 type WordsSettings struct {
 	wordsCursor     int
 	wordsSelection  []int
 	selectionCursor int
+	custom          int
 }
 
 type PunctuationSettings struct {
@@ -41,11 +48,91 @@ type PunctuationSettings struct {
 	savedValue bool
 }
 
+type NumbersSettings struct {
+	enabled    bool
+	savedValue bool
+}
+
+type SymbolsSettings struct {
+	enabled    bool
+	savedValue bool
+}
+
+type WordInputSettings struct {
+	enabled    bool
+	savedValue bool
+}
+
+type FailModeSettings struct {
+	modeIndex  int
+	savedIndex int
+}
+
+type MinWpmSettings struct {
+	wpmIndex   int
+	savedIndex int
+}
+
+type MinAccuracySettings struct {
+	accuracyIndex int
+	savedIndex    int
+}
+
+type LiveStatsSettings struct {
+	enabled    bool
+	savedValue bool
+}
+
+type PaceCaretSettings struct {
+	optionIndex int
+	savedIndex  int
+}
+
+type KeyFilterSettings struct {
+	filterIndex int
+	savedIndex  int
+}
+
+type PunctuationProfileSettings struct {
+	profileIndex int
+	savedIndex   int
+}
+
 type ThemeSettings struct {
 	themeIndex int
 	savedIndex int
 }
 
+type QuoteLengthSettings struct {
+	lengthIndex int
+	savedIndex  int
+}
+
+type WordListSettings struct {
+	listNames  []string
+	listIndex  int
+	savedIndex int
+}
+
+type LanguageSettings struct {
+	languageIndex int
+	savedIndex    int
+}
+
+type FrequencySettings struct {
+	frequencyIndex int
+	savedIndex     int
+	// list is the word list picked on the settings screen, which decides
+	// which tiers are offered.
+	list func() (words.WordList, bool)
+}
+
+type CodeLanguageSettings struct {
+	languages     []string
+	languageIndex int
+	savedIndex    int
+}
+
 func NewSettingsHandler(user *database.ApplicationUser) *SettingsHandler {
 	wordCountSelection := []int{15, 30, 45, 60}
 	timerSelection := []int{15, 30, 60, 120}
@@ -54,12 +141,14 @@ func NewSettingsHandler(user *database.ApplicationUser) *SettingsHandler {
 		timerSelection:  timerSelection,
 		timerCursor:     findTimerIndex(user.Config, timerSelection),
 		selectionCursor: findTimerIndex(user.Config, timerSelection),
+		custom:          customValue(user.Config.Time, timerSelection),
 	}
 
 	wordsSettings := WordsSettings{
 		wordsSelection:  wordCountSelection,
 		wordsCursor:     findWordsIndex(user.Config, wordCountSelection),
 		selectionCursor: findWordsIndex(user.Config, wordCountSelection),
+		custom:          customValue(user.Config.Words, wordCountSelection),
 	}
 
 	punctuationSettings := PunctuationSettings{
@@ -67,15 +156,107 @@ func NewSettingsHandler(user *database.ApplicationUser) *SettingsHandler {
 		savedValue: user.Config.Punctuation,
 	}
 
+	profileIndex := findPunctuationProfileIndex(user.Config)
+	punctuationProfileSettings := PunctuationProfileSettings{
+		profileIndex: profileIndex,
+		savedIndex:   profileIndex,
+	}
+
+	numbersSettings := NumbersSettings{
+		enabled:    user.Config.Numbers,
+		savedValue: user.Config.Numbers,
+	}
+
+	symbolsSettings := SymbolsSettings{
+		enabled:    user.Config.Symbols,
+		savedValue: user.Config.Symbols,
+	}
+
+	filterIndex := findKeyFilterIndex(user.Config)
+	keyFilterSettings := KeyFilterSettings{
+		filterIndex: filterIndex,
+		savedIndex:  filterIndex,
+	}
+
+	wordInputSettings := WordInputSettings{
+		enabled:    user.Config.WordInput,
+		savedValue: user.Config.WordInput,
+	}
+
+	failModeIndex := findFailModeIndex(user.Config)
+	failModeSettings := FailModeSettings{
+		modeIndex:  failModeIndex,
+		savedIndex: failModeIndex,
+	}
+
+	minWpmIndex := findStepIndex(user.Config.MinWpm, minWpmSteps)
+	minWpmSettings := MinWpmSettings{
+		wpmIndex:   minWpmIndex,
+		savedIndex: minWpmIndex,
+	}
+
+	minAccuracyIndex := findStepIndex(user.Config.MinAccuracy, minAccuracySteps)
+	minAccuracySettings := MinAccuracySettings{
+		accuracyIndex: minAccuracyIndex,
+		savedIndex:    minAccuracyIndex,
+	}
+
+	paceIndex := findPaceOptionIndex(user.Config)
+	paceCaretSettings := PaceCaretSettings{
+		optionIndex: paceIndex,
+		savedIndex:  paceIndex,
+	}
+
+	liveStatsSettings := LiveStatsSettings{
+		enabled:    user.Config.LiveStats,
+		savedValue: user.Config.LiveStats,
+	}
+
 	themeSettings := ThemeSettings{
 		themeIndex: GetThemeIndex(user.Config.Theme),
 		savedIndex: GetThemeIndex(user.Config.Theme),
 	}
 
+	quoteLength := int(words.ParseQuoteLength(user.Config.QuoteLength))
+	quoteLengthSettings := QuoteLengthSettings{
+		lengthIndex: quoteLength,
+		savedIndex:  quoteLength,
+	}
+
+	listNames := words.ListNames()
+	wordListSettings := WordListSettings{
+		listNames:  listNames,
+		listIndex:  findWordListIndex(user.Config, listNames),
+		savedIndex: findWordListIndex(user.Config, listNames),
+	}
+
+	languageSettings := LanguageSettings{
+		languageIndex: findLanguageIndex(user.Config),
+		savedIndex:    findLanguageIndex(user.Config),
+	}
+
+	frequency := int(words.ParseFrequency(user.Config.Frequency))
+	frequencySettings := FrequencySettings{
+		frequencyIndex: frequency,
+		savedIndex:     frequency,
+		list: func() (words.WordList, bool) {
+			listName := wordListSettings.listNames[wordListSettings.listIndex]
+			language := words.Languages[languageSettings.languageIndex].Name
+			return words.FindList(words.ResolveList(listName, language))
+		},
+	}
+
+	codeLanguages := append([]string{""}, words.CodeLanguages()...)
+	codeLanguageSettings := CodeLanguageSettings{
+		languages:     codeLanguages,
+		languageIndex: findCodeLanguageIndex(user.Config, codeLanguages),
+		savedIndex:    findCodeLanguageIndex(user.Config, codeLanguages),
+	}
+
 	return &SettingsHandler{
 		BaseStateHandler:  NewBaseStateHandler(StateSettings),
 		settingsCursor:    0,
-		settingSelections: []TestSetting{&timerSettings, &wordsSettings, &punctuationSettings, &themeSettings},
+		settingSelections: []TestSetting{&timerSettings, &wordsSettings, &punctuationSettings, &punctuationProfileSettings, &numbersSettings, &symbolsSettings, &keyFilterSettings, &wordInputSettings, &failModeSettings, &minWpmSettings, &minAccuracySettings, &paceCaretSettings, &liveStatsSettings, &languageSettings, &wordListSettings, &frequencySettings, &themeSettings, &quoteLengthSettings, &codeLanguageSettings},
 		userConfig:        *user.Config,
 	}
 }
@@ -95,10 +276,70 @@ func (h *SettingsHandler) HasUnsavedChanges() bool {
 			if s.enabled != s.savedValue {
 				return true
 			}
+		case *NumbersSettings:
+			if s.enabled != s.savedValue {
+				return true
+			}
+		case *SymbolsSettings:
+			if s.enabled != s.savedValue {
+				return true
+			}
+		case *KeyFilterSettings:
+			if s.filterIndex != s.savedIndex {
+				return true
+			}
+		case *WordInputSettings:
+			if s.enabled != s.savedValue {
+				return true
+			}
+		case *FailModeSettings:
+			if s.modeIndex != s.savedIndex {
+				return true
+			}
+		case *MinWpmSettings:
+			if s.wpmIndex != s.savedIndex {
+				return true
+			}
+		case *MinAccuracySettings:
+			if s.accuracyIndex != s.savedIndex {
+				return true
+			}
+		case *PaceCaretSettings:
+			if s.optionIndex != s.savedIndex {
+				return true
+			}
+		case *LiveStatsSettings:
+			if s.enabled != s.savedValue {
+				return true
+			}
+		case *PunctuationProfileSettings:
+			if s.profileIndex != s.savedIndex {
+				return true
+			}
 		case *ThemeSettings:
 			if s.themeIndex != s.savedIndex {
 				return true
 			}
+		case *QuoteLengthSettings:
+			if s.lengthIndex != s.savedIndex {
+				return true
+			}
+		case *WordListSettings:
+			if s.listIndex != s.savedIndex {
+				return true
+			}
+		case *LanguageSettings:
+			if s.languageIndex != s.savedIndex {
+				return true
+			}
+		case *FrequencySettings:
+			if s.frequencyIndex != s.savedIndex {
+				return true
+			}
+		case *CodeLanguageSettings:
+			if s.languageIndex != s.savedIndex {
+				return true
+			}
 		}
 	}
 	return false
@@ -122,6 +363,27 @@ func (h *SettingsHandler) HandleInput(msg tea.Msg, context *StateContext) (State
 		case "enter":
 			h.settingSelections[h.settingsCursor].SaveSettings(context)
 
+			if profile, ok := h.settingSelections[h.settingsCursor].(*PunctuationProfileSettings); ok && profile.isCustom() {
+				if h.ValidateTransition(StatePunctuationWeights, context) {
+					return NewPunctuationWeightsHandler(context.model.session.User.Config), nil
+				}
+			}
+			if filter, ok := h.settingSelections[h.settingsCursor].(*KeyFilterSettings); ok && filter.isCustom() {
+				if h.ValidateTransition(StateKeyFilterKeys, context) {
+					return NewKeyFilterKeysHandler(context.model.session.User.Config), nil
+				}
+			}
+			if timer, ok := h.settingSelections[h.settingsCursor].(*TimerSettings); ok && timer.isCustom() {
+				if h.ValidateTransition(StateCustomValue, context) {
+					return NewCustomValueHandler(context.model.session.User.Config, customTime), nil
+				}
+			}
+			if words, ok := h.settingSelections[h.settingsCursor].(*WordsSettings); ok && words.isCustom() {
+				if h.ValidateTransition(StateCustomValue, context) {
+					return NewCustomValueHandler(context.model.session.User.Config, customWords), nil
+				}
+			}
+
 		case "up", "k":
 			if h.settingsCursor == 0 {
 				newCursor = len(h.settingSelections) - 1
@@ -149,20 +411,47 @@ func (h *SettingsHandler) HandleInput(msg tea.Msg, context *StateContext) (State
 	return h, nil
 }
 
+// settingsChromeLines is the height of the settings screen around its rows:
+// the title, the help text and a line each for the rows scrolled out of view
+// above and below.
+const settingsChromeLines = 7
+
+// settingsWindow returns the rows [start, end) of the settings screen that fit
+// in height lines, keeping the cursor in view. Each row takes two lines. An
+// unknown height shows every row.
+func settingsWindow(cursor, rows, height int) (int, int) {
+	visible := (height - settingsChromeLines) / 2
+	if height <= 0 || visible >= rows {
+		return 0, rows
+	}
+	visible = max(visible, 1)
+
+	start := min(max(cursor-visible/2, 0), rows-visible)
+	return start, start + visible
+}
+
 func (h *SettingsHandler) Render(m *model) string {
 	termWidth, termHeight := m.width-2, m.height-2
 	settings := style("Settings", m.styles.themeFunc)
 	settings = lipgloss.NewStyle().PaddingBottom(1).Render(settings)
 
 	menuItemsStyle := lipgloss.NewStyle().PaddingTop(1)
+	moreStyle := lipgloss.NewStyle().Faint(true)
+	start, end := settingsWindow(h.settingsCursor, len(h.settingSelections), termHeight)
 	var settingSelection []string
-	for i, choice := range h.settingSelections {
-		choiceShow := choice.render(m.styles)
+	if start > 0 {
+		settingSelection = append(settingSelection, moreStyle.Render(fmt.Sprintf("↑ %d more", start)))
+	}
+	for i := start; i < end; i++ {
+		choiceShow := h.settingSelections[i].render(m.styles)
 
 		choiceShow = wrapWithCursor(h.settingsCursor == i, choiceShow, m.styles.toEnter)
 		choiceShow = menuItemsStyle.Render(choiceShow)
 		settingSelection = append(settingSelection, choiceShow)
 	}
+	if end < len(h.settingSelections) {
+		settingSelection = append(settingSelection, moreStyle.PaddingTop(1).Render(fmt.Sprintf("↓ %d more", len(h.settingSelections)-end)))
+	}
 
 	helpText := lipgloss.NewStyle().Faint(true).Render("\nenter: save, ctrl+q: exit")
 
@@ -176,7 +465,12 @@ func (h *SettingsHandler) Render(m *model) string {
 
 func (t *TimerSettings) render(styles Styles) string {
 	var renderColor StringStyle
-	formattedDuration := formatSettingsDuration(t.timerSelection[t.selectionCursor])
+	var formattedDuration string
+	if t.isCustom() {
+		formattedDuration = customLabel(t.custom, formatSettingsDuration)
+	} else {
+		formattedDuration = formatSettingsDuration(t.timerSelection[t.selectionCursor])
+	}
 	if t.selectionCursor == t.timerCursor {
 		renderColor = styles.themeFunc
 	} else {
@@ -188,7 +482,12 @@ func (t *TimerSettings) render(styles Styles) string {
 
 func (w *WordsSettings) render(styles Styles) string {
 	var renderColor StringStyle
-	numberOfWords := fmt.Sprintf("%d", w.wordsSelection[w.selectionCursor])
+	var numberOfWords string
+	if w.isCustom() {
+		numberOfWords = customLabel(w.custom, strconv.Itoa)
+	} else {
+		numberOfWords = fmt.Sprintf("%d", w.wordsSelection[w.selectionCursor])
+	}
 	if w.selectionCursor == w.wordsCursor {
 		renderColor = styles.themeFunc
 	} else {
@@ -198,13 +497,23 @@ func (w *WordsSettings) render(styles Styles) string {
 	return fmt.Sprintf("%s %s", "Words", selectionsStr)
 }
 
+func (t *TimerSettings) isCustom() bool {
+	return t.selectionCursor == len(t.timerSelection)
+}
+
+func (w *WordsSettings) isCustom() bool {
+	return w.selectionCursor == len(w.wordsSelection)
+}
+
+// findTimerIndex returns the preset matching the configured time, or the
+// custom entry when none does.
 func findTimerIndex(config *database.UserConfig, timerSelection []int) int {
 	for i, num := range timerSelection {
 		if num == config.Time {
 			return i
 		}
 	}
-	return 0
+	return len(timerSelection)
 }
 
 func findWordsIndex(config *database.UserConfig, wordCountSelection []int) int {
@@ -213,19 +522,36 @@ func findWordsIndex(config *database.UserConfig, wordCountSelection []int) int {
 			return i
 		}
 	}
-	return 0
+	return len(wordCountSelection)
+}
+
+// customValue is the configured value when it isn't one of the presets, or 0.
+func customValue(value int, selection []int) int {
+	for _, num := range selection {
+		if num == value {
+			return 0
+		}
+	}
+	return value
+}
+
+func customLabel(value int, format func(int) string) string {
+	if value == 0 {
+		return "custom (enter to edit)"
+	}
+	return "custom " + format(value) + " (enter to edit)"
 }
 
 func (t *TimerSettings) MoveLeft() {
 	if t.selectionCursor == 0 {
-		t.selectionCursor = len(t.timerSelection) - 1
+		t.selectionCursor = len(t.timerSelection)
 	} else {
 		t.selectionCursor--
 	}
 }
 
 func (t *TimerSettings) MoveRight() {
-	if t.selectionCursor == len(t.timerSelection)-1 {
+	if t.selectionCursor == len(t.timerSelection) {
 		t.selectionCursor = 0
 	} else {
 		t.selectionCursor++
@@ -236,6 +562,10 @@ func (t *TimerSettings) SaveSettings(context *StateContext) {
 	if t.selectionCursor != t.timerCursor {
 		t.timerCursor = t.selectionCursor
 	}
+	if t.isCustom() {
+		// Saved once the value has been entered.
+		return
+	}
 	newUserConfig := context.model.session.User.Config
 	newUserConfig.Time = t.timerSelection[t.timerCursor]
 
@@ -247,14 +577,14 @@ func (t *TimerSettings) SaveSettings(context *StateContext) {
 
 func (w *WordsSettings) MoveLeft() {
 	if w.selectionCursor == 0 {
-		w.selectionCursor = len(w.wordsSelection) - 1
+		w.selectionCursor = len(w.wordsSelection)
 	} else {
 		w.selectionCursor--
 	}
 }
 
 func (w *WordsSettings) MoveRight() {
-	if w.selectionCursor == len(w.wordsSelection)-1 {
+	if w.selectionCursor == len(w.wordsSelection) {
 		w.selectionCursor = 0
 	} else {
 		w.selectionCursor++
@@ -265,6 +595,9 @@ func (w *WordsSettings) SaveSettings(context *StateContext) {
 	if w.selectionCursor != w.wordsCursor {
 		w.wordsCursor = w.selectionCursor
 	}
+	if w.isCustom() {
+		return
+	}
 	newUserConfig := context.model.session.User.Config
 	newUserConfig.Words = w.wordsSelection[w.wordsCursor]
 
@@ -305,38 +638,725 @@ func (p *PunctuationSettings) SaveSettings(context *StateContext) {
 		UserConfigToMap(newUserConfig))
 }
 
-func (t *ThemeSettings) render(styles Styles) string {
+func (n *NumbersSettings) render(styles Styles) string {
 	var renderColor StringStyle
-	if t.themeIndex == t.savedIndex {
+	if n.savedValue == n.enabled {
 		renderColor = styles.themeFunc
 	} else {
 		renderColor = styles.toEnter
 	}
-	themeName := AvailableThemes[t.themeIndex].Name
-	selectionsStr := "[" + style(themeName, renderColor) + "]"
-	return fmt.Sprintf("%s %s", "Theme", selectionsStr)
+	selectionsStr := "[" + style(fmt.Sprintf("%t", n.enabled), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Numbers", selectionsStr)
 }
 
-func (t *ThemeSettings) MoveLeft() {
-	if t.themeIndex == 0 {
-		t.themeIndex = len(AvailableThemes) - 1
+func (n *NumbersSettings) MoveLeft() {
+	n.enabled = false
+}
+
+func (n *NumbersSettings) MoveRight() {
+	n.enabled = true
+}
+
+func (n *NumbersSettings) SaveSettings(context *StateContext) {
+	n.savedValue = n.enabled
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.Numbers = n.enabled
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (s *SymbolsSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if s.savedValue == s.enabled {
+		renderColor = styles.themeFunc
 	} else {
-		t.themeIndex--
+		renderColor = styles.toEnter
 	}
+	selectionsStr := "[" + style(fmt.Sprintf("%t", s.enabled), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Symbols", selectionsStr)
 }
 
-func (t *ThemeSettings) MoveRight() {
-	if t.themeIndex == len(AvailableThemes)-1 {
-		t.themeIndex = 0
+func (s *SymbolsSettings) MoveLeft() {
+	s.enabled = false
+}
+
+func (s *SymbolsSettings) MoveRight() {
+	s.enabled = true
+}
+
+func (s *SymbolsSettings) SaveSettings(context *StateContext) {
+	s.savedValue = s.enabled
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.Symbols = s.enabled
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (w *WordInputSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if w.savedValue == w.enabled {
+		renderColor = styles.themeFunc
 	} else {
-		t.themeIndex++
+		renderColor = styles.toEnter
 	}
+	selectionsStr := "[" + style(fmt.Sprintf("%t", w.enabled), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Word input", selectionsStr)
 }
 
-func (t *ThemeSettings) SaveSettings(context *StateContext) {
-	t.savedIndex = t.themeIndex
+func (w *WordInputSettings) MoveLeft() {
+	w.enabled = false
+}
+
+func (w *WordInputSettings) MoveRight() {
+	w.enabled = true
+}
+
+func (w *WordInputSettings) SaveSettings(context *StateContext) {
+	w.savedValue = w.enabled
 	newUserConfig := context.model.session.User.Config
-	newUserConfig.Theme = AvailableThemes[t.themeIndex].Name
+	newUserConfig.WordInput = w.enabled
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (f *FailModeSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if f.modeIndex == f.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(failModeLabels[failModeNames[f.modeIndex]], renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Fail mode", selectionsStr)
+}
+
+func findFailModeIndex(config *database.UserConfig) int {
+	for i, mode := range failModeNames {
+		if mode == config.FailMode {
+			return i
+		}
+	}
+	return 0
+}
+
+func (f *FailModeSettings) MoveLeft() {
+	if f.modeIndex == 0 {
+		f.modeIndex = len(failModeNames) - 1
+	} else {
+		f.modeIndex--
+	}
+}
+
+func (f *FailModeSettings) MoveRight() {
+	if f.modeIndex == len(failModeNames)-1 {
+		f.modeIndex = 0
+	} else {
+		f.modeIndex++
+	}
+}
+
+func (f *FailModeSettings) SaveSettings(context *StateContext) {
+	f.savedIndex = f.modeIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.FailMode = failModeNames[f.modeIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+// findStepIndex returns the step matching value, or the first, which is off,
+// when none does.
+func findStepIndex(value int, steps []int) int {
+	for i, step := range steps {
+		if step == value {
+			return i
+		}
+	}
+	return 0
+}
+
+func formatFloor(value int, unit string) string {
+	if value == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d%s", value, unit)
+}
+
+func (w *MinWpmSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if w.wpmIndex == w.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(formatFloor(minWpmSteps[w.wpmIndex], " wpm"), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Minimum WPM", selectionsStr)
+}
+
+func (w *MinWpmSettings) MoveLeft() {
+	if w.wpmIndex == 0 {
+		w.wpmIndex = len(minWpmSteps) - 1
+	} else {
+		w.wpmIndex--
+	}
+}
+
+func (w *MinWpmSettings) MoveRight() {
+	if w.wpmIndex == len(minWpmSteps)-1 {
+		w.wpmIndex = 0
+	} else {
+		w.wpmIndex++
+	}
+}
+
+func (w *MinWpmSettings) SaveSettings(context *StateContext) {
+	w.savedIndex = w.wpmIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.MinWpm = minWpmSteps[w.wpmIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (a *MinAccuracySettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if a.accuracyIndex == a.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(formatFloor(minAccuracySteps[a.accuracyIndex], "%"), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Minimum accuracy", selectionsStr)
+}
+
+func (a *MinAccuracySettings) MoveLeft() {
+	if a.accuracyIndex == 0 {
+		a.accuracyIndex = len(minAccuracySteps) - 1
+	} else {
+		a.accuracyIndex--
+	}
+}
+
+func (a *MinAccuracySettings) MoveRight() {
+	if a.accuracyIndex == len(minAccuracySteps)-1 {
+		a.accuracyIndex = 0
+	} else {
+		a.accuracyIndex++
+	}
+}
+
+func (a *MinAccuracySettings) SaveSettings(context *StateContext) {
+	a.savedIndex = a.accuracyIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.MinAccuracy = minAccuracySteps[a.accuracyIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (p *PaceCaretSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if p.optionIndex == p.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(paceOptions[p.optionIndex].label(), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Pace caret", selectionsStr)
+}
+
+func findPaceOptionIndex(config *database.UserConfig) int {
+	for i, option := range paceOptions {
+		if option.mode == config.PaceCaret && (option.mode != paceTarget || option.wpm == config.PaceWpm) {
+			return i
+		}
+	}
+	return 0
+}
+
+func (p *PaceCaretSettings) MoveLeft() {
+	if p.optionIndex == 0 {
+		p.optionIndex = len(paceOptions) - 1
+	} else {
+		p.optionIndex--
+	}
+}
+
+func (p *PaceCaretSettings) MoveRight() {
+	if p.optionIndex == len(paceOptions)-1 {
+		p.optionIndex = 0
+	} else {
+		p.optionIndex++
+	}
+}
+
+func (p *PaceCaretSettings) SaveSettings(context *StateContext) {
+	p.savedIndex = p.optionIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.PaceCaret = paceOptions[p.optionIndex].mode
+	newUserConfig.PaceWpm = paceOptions[p.optionIndex].wpm
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (l *LiveStatsSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if l.savedValue == l.enabled {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(fmt.Sprintf("%t", l.enabled), renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Live stats", selectionsStr)
+}
+
+func (l *LiveStatsSettings) MoveLeft() {
+	l.enabled = false
+}
+
+func (l *LiveStatsSettings) MoveRight() {
+	l.enabled = true
+}
+
+func (l *LiveStatsSettings) SaveSettings(context *StateContext) {
+	l.savedValue = l.enabled
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.LiveStats = l.enabled
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (k *KeyFilterSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if k.filterIndex == k.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	filter := words.KeyFilterNames[k.filterIndex]
+	if label := words.ParseKeyFilter(filter, "").Label(); label != "" {
+		filter = label
+	}
+	if k.isCustom() {
+		filter += " (enter to edit)"
+	}
+	selectionsStr := "[" + style(filter, renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Key Filter", selectionsStr)
+}
+
+func (k *KeyFilterSettings) isCustom() bool {
+	return words.KeyFilterNames[k.filterIndex] == words.CustomKeyFilter
+}
+
+func findKeyFilterIndex(config *database.UserConfig) int {
+	for i, filter := range words.KeyFilterNames {
+		if filter == config.KeyFilter {
+			return i
+		}
+	}
+	return 0
+}
+
+func (k *KeyFilterSettings) MoveLeft() {
+	if k.filterIndex == 0 {
+		k.filterIndex = len(words.KeyFilterNames) - 1
+	} else {
+		k.filterIndex--
+	}
+}
+
+func (k *KeyFilterSettings) MoveRight() {
+	if k.filterIndex == len(words.KeyFilterNames)-1 {
+		k.filterIndex = 0
+	} else {
+		k.filterIndex++
+	}
+}
+
+func (k *KeyFilterSettings) SaveSettings(context *StateContext) {
+	k.savedIndex = k.filterIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.KeyFilter = words.KeyFilterNames[k.filterIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (p *PunctuationProfileSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if p.profileIndex == p.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	profile := words.PunctuationProfileNames[p.profileIndex]
+	if p.isCustom() {
+		profile += " (enter to edit)"
+	}
+	selectionsStr := "[" + style(profile, renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Punctuation Profile", selectionsStr)
+}
+
+func (p *PunctuationProfileSettings) isCustom() bool {
+	return words.PunctuationProfileNames[p.profileIndex] == words.CustomPunctuation
+}
+
+func findPunctuationProfileIndex(config *database.UserConfig) int {
+	name := words.PunctuationProfileName(config.PunctuationProfile)
+	for i, profile := range words.PunctuationProfileNames {
+		if profile == name {
+			return i
+		}
+	}
+	return 0
+}
+
+func (p *PunctuationProfileSettings) MoveLeft() {
+	if p.profileIndex == 0 {
+		p.profileIndex = len(words.PunctuationProfileNames) - 1
+	} else {
+		p.profileIndex--
+	}
+}
+
+func (p *PunctuationProfileSettings) MoveRight() {
+	if p.profileIndex == len(words.PunctuationProfileNames)-1 {
+		p.profileIndex = 0
+	} else {
+		p.profileIndex++
+	}
+}
+
+func (p *PunctuationProfileSettings) SaveSettings(context *StateContext) {
+	p.savedIndex = p.profileIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.PunctuationProfile = words.PunctuationProfileNames[p.profileIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (t *ThemeSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if t.themeIndex == t.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	themeName := AvailableThemes[t.themeIndex].Name
+	selectionsStr := "[" + style(themeName, renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Theme", selectionsStr)
+}
+
+func (t *ThemeSettings) MoveLeft() {
+	if t.themeIndex == 0 {
+		t.themeIndex = len(AvailableThemes) - 1
+	} else {
+		t.themeIndex--
+	}
+}
+
+func (t *ThemeSettings) MoveRight() {
+	if t.themeIndex == len(AvailableThemes)-1 {
+		t.themeIndex = 0
+	} else {
+		t.themeIndex++
+	}
+}
+
+func (t *ThemeSettings) SaveSettings(context *StateContext) {
+	t.savedIndex = t.themeIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.Theme = AvailableThemes[t.themeIndex].Name
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (q *QuoteLengthSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if q.lengthIndex == q.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(words.QuoteLengthNames[q.lengthIndex], renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Quote Length", selectionsStr)
+}
+
+func (q *QuoteLengthSettings) MoveLeft() {
+	if q.lengthIndex == 0 {
+		q.lengthIndex = len(words.QuoteLengthNames) - 1
+	} else {
+		q.lengthIndex--
+	}
+}
+
+func (q *QuoteLengthSettings) MoveRight() {
+	if q.lengthIndex == len(words.QuoteLengthNames)-1 {
+		q.lengthIndex = 0
+	} else {
+		q.lengthIndex++
+	}
+}
+
+func (q *QuoteLengthSettings) SaveSettings(context *StateContext) {
+	q.savedIndex = q.lengthIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.QuoteLength = words.QuoteLengthNames[q.lengthIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (w *WordListSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if w.listIndex == w.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	listName := w.listNames[w.listIndex]
+	if listName == words.DefaultList {
+		listName = "Language default"
+	}
+	selectionsStr := "[" + style(listName, renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Word List", selectionsStr)
+}
+
+func findWordListIndex(config *database.UserConfig, listNames []string) int {
+	for i, name := range listNames {
+		if name == config.WordList {
+			return i
+		}
+	}
+	return 0
+}
+
+func (w *WordListSettings) MoveLeft() {
+	if w.listIndex == 0 {
+		w.listIndex = len(w.listNames) - 1
+	} else {
+		w.listIndex--
+	}
+}
+
+func (w *WordListSettings) MoveRight() {
+	if w.listIndex == len(w.listNames)-1 {
+		w.listIndex = 0
+	} else {
+		w.listIndex++
+	}
+}
+
+func (w *WordListSettings) SaveSettings(context *StateContext) {
+	w.savedIndex = w.listIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.WordList = w.listNames[w.listIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (l *LanguageSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if l.languageIndex == l.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(words.Languages[l.languageIndex].Name, renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Language", selectionsStr)
+}
+
+func findLanguageIndex(config *database.UserConfig) int {
+	for i, lang := range words.Languages {
+		if lang.Name == config.Language {
+			return i
+		}
+	}
+	return 0
+}
+
+func (l *LanguageSettings) MoveLeft() {
+	if l.languageIndex == 0 {
+		l.languageIndex = len(words.Languages) - 1
+	} else {
+		l.languageIndex--
+	}
+}
+
+func (l *LanguageSettings) MoveRight() {
+	if l.languageIndex == len(words.Languages)-1 {
+		l.languageIndex = 0
+	} else {
+		l.languageIndex++
+	}
+}
+
+func (l *LanguageSettings) SaveSettings(context *StateContext) {
+	l.savedIndex = l.languageIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.Language = words.Languages[l.languageIndex].Name
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (f *FrequencySettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if f.frequencyIndex == f.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	selectionsStr := "[" + style(words.FrequencyNames[f.frequencyIndex], renderColor) + "]"
+	if note := f.hiddenNote(); note != "" {
+		selectionsStr += " " + style(note, styles.toEnter)
+	}
+	return fmt.Sprintf("%s %s", "Frequency", selectionsStr)
+}
+
+// offered reports whether the tier at index picks out part of the chosen
+// list. Lists that can't be found offer every tier.
+func (f *FrequencySettings) offered(index int) bool {
+	if f.list == nil {
+		return true
+	}
+	list, ok := f.list()
+	return !ok || list.HasTier(words.Frequency(index))
+}
+
+// hiddenNote says which tiers aren't offered because the list is no longer
+// than them.
+func (f *FrequencySettings) hiddenNote() string {
+	var hidden []string
+	for i, name := range words.FrequencyNames {
+		if !f.offered(i) {
+			hidden = append(hidden, name)
+		}
+	}
+	if len(hidden) == 0 {
+		return ""
+	}
+	list, _ := f.list()
+	return fmt.Sprintf("(%s hidden: the list has %d words)", strings.Join(hidden, ", "), len(list.Words))
+}
+
+func (f *FrequencySettings) MoveLeft() {
+	for {
+		if f.frequencyIndex == 0 {
+			f.frequencyIndex = len(words.FrequencyNames) - 1
+		} else {
+			f.frequencyIndex--
+		}
+		if f.offered(f.frequencyIndex) {
+			return
+		}
+	}
+}
+
+func (f *FrequencySettings) MoveRight() {
+	for {
+		if f.frequencyIndex == len(words.FrequencyNames)-1 {
+			f.frequencyIndex = 0
+		} else {
+			f.frequencyIndex++
+		}
+		if f.offered(f.frequencyIndex) {
+			return
+		}
+	}
+}
+
+func (f *FrequencySettings) SaveSettings(context *StateContext) {
+	f.savedIndex = f.frequencyIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.Frequency = words.FrequencyNames[f.frequencyIndex]
+
+	database.UpdateUserConfigStandalone(
+		context.model.context.UserRepository,
+		context.model.session.User.Id,
+		UserConfigToMap(newUserConfig))
+}
+
+func (c *CodeLanguageSettings) render(styles Styles) string {
+	var renderColor StringStyle
+	if c.languageIndex == c.savedIndex {
+		renderColor = styles.themeFunc
+	} else {
+		renderColor = styles.toEnter
+	}
+	language := c.languages[c.languageIndex]
+	if language == "" {
+		language = "any"
+	}
+	selectionsStr := "[" + style(language, renderColor) + "]"
+	return fmt.Sprintf("%s %s", "Code Language", selectionsStr)
+}
+
+func findCodeLanguageIndex(config *database.UserConfig, languages []string) int {
+	for i, language := range languages {
+		if language == config.CodeLanguage {
+			return i
+		}
+	}
+	return 0
+}
+
+func (c *CodeLanguageSettings) MoveLeft() {
+	if c.languageIndex == 0 {
+		c.languageIndex = len(c.languages) - 1
+	} else {
+		c.languageIndex--
+	}
+}
+
+func (c *CodeLanguageSettings) MoveRight() {
+	if c.languageIndex == len(c.languages)-1 {
+		c.languageIndex = 0
+	} else {
+		c.languageIndex++
+	}
+}
+
+func (c *CodeLanguageSettings) SaveSettings(context *StateContext) {
+	c.savedIndex = c.languageIndex
+	newUserConfig := context.model.session.User.Config
+	newUserConfig.CodeLanguage = c.languages[c.languageIndex]
 
 	database.UpdateUserConfigStandalone(
 		context.model.context.UserRepository,
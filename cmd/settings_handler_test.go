@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"termtyper/database"
+	"termtyper/words"
+
+	"charm.land/lipgloss/v2"
+	"github.com/muesli/termenv"
+)
+
+func TestFrequencySettingsSkipsTiersLongerThanList(t *testing.T) {
+	config := database.DefaultConfig
+	config.Language = "German"
+	h := NewSettingsHandler(&database.ApplicationUser{Config: &config})
+
+	var frequency *FrequencySettings
+	for _, setting := range h.settingSelections {
+		if f, ok := setting.(*FrequencySettings); ok {
+			frequency = f
+		}
+	}
+
+	list, ok := frequency.list()
+	if !ok || list.HasTier(words.FrequencyTop200) {
+		t.Fatalf("expected the German list to be shorter than top200, found %t with %d words", ok, len(list.Words))
+	}
+
+	frequency.MoveRight()
+	if got := words.FrequencyNames[frequency.frequencyIndex]; got != "rare" {
+		t.Errorf("expected moving right from all to skip to rare, got %s", got)
+	}
+	frequency.MoveLeft()
+	if got := words.FrequencyNames[frequency.frequencyIndex]; got != "all" {
+		t.Errorf("expected moving left from rare to skip back to all, got %s", got)
+	}
+	if frequency.hiddenNote() == "" {
+		t.Error("expected the hidden tier to be noted")
+	}
+}
+
+func TestSettingsScrollFitsSmallTerminal(t *testing.T) {
+	config := database.DefaultConfig
+	h := NewSettingsHandler(&database.ApplicationUser{Config: &config})
+	m := &model{width: 80, height: 24, styles: createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")}
+
+	for cursor, setting := range h.settingSelections {
+		h.settingsCursor = cursor
+		view := h.Render(m)
+		if height := lipgloss.Height(view); height > m.height {
+			t.Errorf("cursor %d: settings take %d lines, more than the terminal's %d", cursor, height, m.height)
+		}
+		selected := dropAnsiCodes(setting.render(m.styles))
+		if !strings.Contains(dropAnsiCodes(view), selected) {
+			t.Errorf("cursor %d: selected row %q scrolled out of view", cursor, selected)
+		}
+	}
+}
+
+func TestSettingsWindow(t *testing.T) {
+	tests := []struct {
+		cursor, rows, height int
+		start, end           int
+	}{
+		{cursor: 0, rows: 19, height: 0, start: 0, end: 19},
+		{cursor: 0, rows: 19, height: 60, start: 0, end: 19},
+		{cursor: 0, rows: 19, height: 22, start: 0, end: 7},
+		{cursor: 10, rows: 19, height: 22, start: 7, end: 14},
+		{cursor: 18, rows: 19, height: 22, start: 12, end: 19},
+		{cursor: 5, rows: 19, height: 3, start: 5, end: 6},
+	}
+	for _, tt := range tests {
+		start, end := settingsWindow(tt.cursor, tt.rows, tt.height)
+		if start != tt.start || end != tt.end {
+			t.Errorf("settingsWindow(%d, %d, %d) = %d, %d, want %d, %d",
+				tt.cursor, tt.rows, tt.height, start, end, tt.start, tt.end)
+		}
+	}
+}
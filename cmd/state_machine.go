@@ -20,6 +20,17 @@ const (
 	StateSettingsUnsavedPrompt
 	StateUserSettings
 	StateReplay
+	StateQuoteTest
+	StateCodeTest
+	StateSeedInput
+	StatePracticeTest
+	StateBookSelect
+	StateBookTest
+	StatePunctuationWeights
+	StateKeyFilterKeys
+	StateMistakesTest
+	StateCustomValue
+	StateHistory
 )
 
 type StateTransition struct {
@@ -55,8 +66,15 @@ func NewStateMachine(m *model) *StateMachine {
 				StateTimerTest,
 				StateZenMode,
 				StateWordCountTest,
+				StateQuoteTest,
+				StateCodeTest,
+				StateSeedInput,
+				StatePracticeTest,
+				StateMistakesTest,
+				StateBookSelect,
 				StateSettings,
 				StateUserSettings,
+				StateHistory,
 			},
 			StateTimerTest: {
 				StateResults,
@@ -74,10 +92,18 @@ func NewStateMachine(m *model) *StateMachine {
 				StateReplay,
 				StateTimerTest,
 				StateWordCountTest,
+				StateQuoteTest,
+				StateCodeTest,
+				StatePracticeTest,
+				StateBookTest,
+				StateMistakesTest,
 			},
 			StateSettings: {
 				StateMainMenu,
 				StateSettingsUnsavedPrompt,
+				StatePunctuationWeights,
+				StateKeyFilterKeys,
+				StateCustomValue,
 			},
 			StateSettingsUnsavedPrompt: {
 				StateMainMenu,
@@ -89,6 +115,47 @@ func NewStateMachine(m *model) *StateMachine {
 			StateReplay: {
 				StateMainMenu,
 			},
+			StateQuoteTest: {
+				StateResults,
+				StateMainMenu,
+			},
+			StateCodeTest: {
+				StateResults,
+				StateMainMenu,
+			},
+			StateSeedInput: {
+				StateTimerTest,
+				StateWordCountTest,
+				StateMainMenu,
+			},
+			StatePracticeTest: {
+				StateResults,
+				StateMainMenu,
+			},
+			StateBookSelect: {
+				StateBookTest,
+				StateMainMenu,
+			},
+			StateBookTest: {
+				StateResults,
+				StateMainMenu,
+			},
+			StatePunctuationWeights: {
+				StateSettings,
+			},
+			StateKeyFilterKeys: {
+				StateSettings,
+			},
+			StateMistakesTest: {
+				StateResults,
+				StateMainMenu,
+			},
+			StateCustomValue: {
+				StateSettings,
+			},
+			StateHistory: {
+				StateMainMenu,
+			},
 		},
 		handlers: make(map[StateType]StateHandler),
 	}
@@ -105,6 +172,17 @@ func NewStateMachine(m *model) *StateMachine {
 	sm.handlers[StateSettingsUnsavedPrompt] = &UnsavedPromptHandler{}
 	sm.handlers[StateUserSettings] = &UserSettingsHandler{}
 	sm.handlers[StateReplay] = &ReplayHandler{}
+	sm.handlers[StateQuoteTest] = &QuoteTestHandler{}
+	sm.handlers[StateCodeTest] = &CodeTestHandler{}
+	sm.handlers[StateSeedInput] = &SeedInputHandler{}
+	sm.handlers[StatePracticeTest] = &PracticeTestHandler{}
+	sm.handlers[StateBookSelect] = &BookSelectHandler{}
+	sm.handlers[StateBookTest] = &BookTestHandler{}
+	sm.handlers[StatePunctuationWeights] = &PunctuationWeightsHandler{}
+	sm.handlers[StateKeyFilterKeys] = &KeyFilterKeysHandler{}
+	sm.handlers[StateMistakesTest] = &MistakesTestHandler{}
+	sm.handlers[StateCustomValue] = &CustomValueHandler{}
+	sm.handlers[StateHistory] = &HistoryHandler{}
 
 	return sm
 }
@@ -26,7 +26,9 @@ func TestNewStateMachine(t *testing.T) {
 	expectedHandlers := []StateType{
 		StatePreAuth, StateLogin, StateRegister, StateMainMenu,
 		StateTimerTest, StateZenMode, StateWordCountTest,
-		StateResults, StateSettings, StateReplay,
+		StateResults, StateSettings, StateReplay, StateQuoteTest, StateCodeTest, StateSeedInput, StatePracticeTest,
+		StateBookSelect, StateBookTest, StatePunctuationWeights,
+		StateKeyFilterKeys, StateMistakesTest, StateCustomValue, StateHistory,
 	}
 
 	for _, stateType := range expectedHandlers {
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/stopwatch"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// stopwatchMode is what sets one stopwatch test apart from the others. The
+// handler of each mode embeds a stopwatchTest and fills in the rest.
+type stopwatchMode interface {
+	StateHandler
+	// testType and testValue identify the test in the history and pick the
+	// pace to type against.
+	testType() string
+	testValue() int
+	// label is shown next to the clock, or nothing when it is empty.
+	label() string
+	// restart starts a new test of the mode for ctrl+r.
+	restart(context *StateContext) StateHandler
+	// finish saves what the mode keeps of a finished test beyond the stats
+	// every test keeps, and adds its details to the results.
+	finish(context *StateContext, testID int64, results *ResultsHandler)
+}
+
+// stopwatchTest is a test that runs until its text is typed. It has the
+// clock, pausing, typing, the failure and integrity checks and the saving of
+// results that every stopwatch mode shares.
+type stopwatchTest struct {
+	*BaseStateHandler
+	stopwatch StopWatch
+	base      TestBase
+	mode      stopwatchMode
+	// seed is the seed code the text came from, nil when it isn't seeded.
+	seed *SeedCode
+	// typesLines lets enter and tab type a newline and a tab.
+	typesLines bool
+	completed  bool
+}
+
+func newStopwatchTest(state StateType, menu MainMenuHandler, text []rune, mode stopwatchMode) *stopwatchTest {
+	return &stopwatchTest{
+		BaseStateHandler: NewBaseStateHandler(state),
+		stopwatch: StopWatch{
+			stopwatch: stopwatch.New(),
+			isRunning: false,
+		},
+		base: TestBase{
+			wordsToEnter:  text,
+			inputBuffer:   make([]rune, 0),
+			rawInputCount: 0,
+			mistakes: mistakes{
+				mistakesAt:     make(map[int]bool, 0),
+				rawMistakesCnt: 0,
+			},
+			cursor:    0,
+			mainMenu:  menu,
+			wordInput: menu.currentUser.Config.WordInput,
+			failRules: newFailureRules(menu.currentUser.Config),
+		},
+		mode:      mode,
+		completed: false,
+	}
+}
+
+func (h *stopwatchTest) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	if cmd, handled := handlePause(msg, &h.stopwatch); handled {
+		return h.mode, cmd
+	}
+	h.base.movePace(h.stopwatch.Elapsed())
+
+	var commands []tea.Cmd
+	switch msg := msg.(type) {
+	case stopwatch.StartStopMsg:
+		stopwatchUpdate, cmdUpdate := h.stopwatch.stopwatch.Update(msg)
+		h.stopwatch.stopwatch = stopwatchUpdate
+		commands = append(commands, cmdUpdate)
+
+	case stopwatch.TickMsg:
+		stopwatchUpdate, cmdUpdate := h.stopwatch.stopwatch.Update(msg)
+		h.stopwatch.stopwatch = stopwatchUpdate
+		commands = append(commands, cmdUpdate)
+
+		elapsedSeconds := h.stopwatch.Elapsed().Seconds()
+		if int(elapsedSeconds) > len(h.base.wpmEachSecond) {
+			elapsedMinutes := elapsedSeconds / 60.0
+			if elapsedMinutes > 0 {
+				h.base.wpmEachSecond = append(h.base.wpmEachSecond, h.base.calculateNormalizedWpm(elapsedMinutes))
+				if h.base.checkSecond() {
+					results := h.calculateResults(context.model, context)
+					return &results, tea.Batch(commands...)
+				}
+			}
+		}
+
+	case tea.PasteMsg:
+		notePaste(msg, &h.base)
+
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "esc":
+			if h.ValidateTransition(StateMainMenu, context) {
+				return NewMainMenuHandler(context.model.session.User, context.model), nil
+			}
+		case "ctrl+q":
+			return NewMainMenuHandler(context.model.session.User, context.model), nil
+		case "ctrl+r":
+			return h.mode.restart(context), nil
+
+		case "backspace":
+			handleBackspace(&h.base)
+			recordInputBackspace(&h.base, h.stopwatch.Elapsed().Milliseconds())
+		case "ctrl+t":
+			handleCtrlBackspace(&h.base)
+		default:
+			if len(msg.Text) > 0 || msg.String() == "space" || h.typesLines && (msg.String() == "enter" || msg.String() == "tab") {
+				if !h.stopwatch.isRunning {
+					h.stopwatch.startTime = time.Now()
+					commands = append(commands, h.stopwatch.stopwatch.Init())
+					h.stopwatch.isRunning = true
+					startPace(context, &h.base, h.mode.testType(), h.mode.testValue())
+				}
+
+				mistakesBefore := h.base.mistakes.rawMistakesCnt
+				handleCharacterInputFromMsg(msg, &h.base)
+				recordInput(msg, &h.base, h.stopwatch.Elapsed().Milliseconds())
+				if h.base.checkKeystroke(mistakesBefore) {
+					results := h.calculateResults(context.model, context)
+					return &results, tea.Batch(commands...)
+				}
+			}
+		}
+	}
+
+	if len(h.base.wordsToEnter) == len(h.base.inputBuffer) &&
+		!h.base.mistakes.mistakesAt[len(h.base.inputBuffer)-1] {
+		results := h.calculateResults(context.model, context)
+		return &results, tea.Batch(commands...)
+	}
+
+	return h.mode, tea.Batch(commands...)
+}
+
+// header is the line above the text: the clock and what is shown next to it.
+func (h *stopwatchTest) header(m *model) string {
+	stopwatchViewSeconds := strconv.FormatFloat(h.stopwatch.Elapsed().Seconds(), 'f', 0, 64) + "s"
+	header := style(stopwatchViewSeconds, m.styles.themeFunc)
+	header += pausedLabel(&h.stopwatch, m.styles)
+	if label := h.mode.label(); label != "" {
+		header += "  " + style(label, m.styles.toEnter)
+	}
+	if h.base.pace.ghost != nil {
+		header += "  " + style("racing ghost", m.styles.toEnter)
+	}
+	if h.base.mainMenu.currentUser.Config.LiveStats {
+		header += "\n" + h.base.renderLiveStats(h.stopwatch.Elapsed(), m.styles)
+	}
+	return header
+}
+
+func (h *stopwatchTest) Render(m *model) string {
+	termWidth, termHeight := m.width-2, m.height-2
+	s := ""
+	paragraphView := h.base.renderParagraph(lineLenLimit, m.styles)
+	lines := strings.Split(paragraphView, "\n")
+	cursorLine := findCursorLine(lines, h.base.displayCursor())
+
+	linesAroundCursor := strings.Join(getLinesAroundCursor(lines, cursorLine), "\n")
+
+	s += positionVertically(termHeight)
+	avgLineLen := averageLineLen(lines)
+	indentBy := uint(math.Max(0, float64(termWidth/2-avgLineLen/2)))
+
+	s += m.indent(h.header(m), indentBy) + "\n\n" + m.indent(linesAroundCursor, indentBy)
+	s += "\n\n\n"
+	s += lipgloss.PlaceHorizontal(termWidth, lipgloss.Center, style("ctrl+r to restart, ctrl+q to menu", m.styles.toEnter))
+
+	return s
+}
+
+func (h *stopwatchTest) ValidateTransition(to StateType, context *StateContext) bool {
+	validTransitions := context.transitionMap[h.GetStateType()]
+	for _, validState := range validTransitions {
+		if validState == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *stopwatchTest) calculateResults(m *model, context *StateContext) ResultsHandler {
+	h.base.integrity = checkIntegrity(h.base)
+	elapsedMinutes := h.stopwatch.Elapsed().Minutes()
+	wpm := h.base.calculateNormalizedWpm(elapsedMinutes)
+	wpmChart := NewWPMChartBubble(m.width/2, m.height/2)
+	wpmChart.UpdateData(h.base.wpmEachSecond)
+
+	accuracy := h.base.calculateAccuracy()
+	chars := h.base.characterStats()
+
+	testID := saveTestResult(context, testOutcome{
+		testType:  h.mode.testType(),
+		testValue: h.mode.testValue(),
+		duration:  h.stopwatch.Elapsed(),
+		wpm:       wpm,
+		accuracy:  accuracy,
+		seed:      h.seed,
+		base:      h.base,
+	})
+	saveBigramStats(context, testID, h.base)
+	saveMissedWords(context, testID, h.base)
+	saveKeyStats(context, testID, h.base)
+
+	results := ResultsHandler{
+		testType:      h.mode.testType(),
+		wpm:           int(wpm),
+		accuracy:      accuracy,
+		rawWpm:        int(h.base.calculateRawWpm(elapsedMinutes)),
+		cpm:           h.base.calculateCpm(elapsedMinutes),
+		chars:         chars,
+		time:          h.stopwatch.Elapsed(),
+		test:          h.base,
+		wpmEachSecond: h.base.wpmEachSecond,
+		mainMenu:      h.base.mainMenu,
+		resultsSelection: []string{
+			"Next Test",
+			"Main Menu",
+			"Replay",
+			"Practice Mistakes",
+		},
+		wpmChart: wpmChart,
+		heatmap:  testHeatmap(h.base),
+	}
+	if h.seed != nil {
+		saveReplay(context, testID, h.seed.String(), h.base)
+		results.seed = h.seed.String()
+		results.raceResult = raceResult(h.base)
+		results.resultsSelection = append(results.resultsSelection, "Race Ghost")
+	}
+	h.mode.finish(context, testID, &results)
+	return results
+}
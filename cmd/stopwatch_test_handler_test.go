@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"termtyper/words"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestStopwatchTestStaysInItsMode(t *testing.T) {
+	m := newGuestModel()
+	context := &StateContext{model: m}
+	menu := MainMenuHandler{
+		wordTestWordGenerator: words.NewGenerator(),
+		currentUser:           m.session.User,
+	}
+	h := NewMistakesTestHandler(menu, []string{"fox"})
+	if got := h.label(); got != "practising 1 missed words" {
+		t.Errorf("unexpected label %q", got)
+	}
+
+	var handler StateHandler = h
+	for _, r := range string(h.base.wordsToEnter) {
+		if _, ok := handler.(*MistakesTestHandler); !ok {
+			t.Fatalf("expected to stay in the mistakes test while typing, got %T", handler)
+		}
+		handler, _ = handler.HandleInput(tea.KeyPressMsg{Code: r, Text: string(r)}, context)
+	}
+
+	results, ok := handler.(*ResultsHandler)
+	if !ok {
+		t.Fatalf("expected the finished test to show results, got %T", handler)
+	}
+	if results.testType != "mistakes" || results.accuracy != 100 {
+		t.Errorf("unexpected results %q at %.1f%% accuracy", results.testType, results.accuracy)
+	}
+	if results.heatmap == nil {
+		t.Error("expected the results to come with the test's heatmap")
+	}
+
+	restarted, _ := h.HandleInput(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl}, context)
+	if again, ok := restarted.(*MistakesTestHandler); !ok || again == h {
+		t.Errorf("expected ctrl+r to start another mistakes test, got %T", restarted)
+	}
+}
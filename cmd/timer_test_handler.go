@@ -10,16 +10,43 @@ import (
 	"charm.land/lipgloss/v2"
 )
 
+const (
+	// streamLookahead is how close the cursor may get to the end of the text
+	// before a timer test asks the generator for more.
+	streamLookahead = 200
+	streamChunk     = 50
+)
+
 type TimerTestHandler struct {
 	*BaseStateHandler
 	base      TestBase
 	completed bool
 	timer     Timer
+	seed      SeedCode
+	// retrySeed restarts the test on the same text, for tests started from a
+	// seed code.
+	retrySeed bool
 }
 
 func NewTimerTestHandler(menu MainMenuHandler) *TimerTestHandler {
-	testDuration := time.Duration(menu.currentUser.Config.Time) * time.Second
-	menu.timerTestWordGenerator.Punctuation = menu.currentUser.Config.Punctuation
+	h := NewSeededTimerTestHandler(menu, newSeedCode("timer", menu.currentUser.Config))
+	h.retrySeed = false
+	return h
+}
+
+// NewSeededTimerTestHandler starts a timer test whose text and settings come
+// from a seed code rather than the user's config. Restarting it keeps the
+// seed.
+func NewSeededTimerTestHandler(menu MainMenuHandler, seed SeedCode) *TimerTestHandler {
+	testDuration := time.Duration(seed.Value) * time.Second
+	menu.timerTestWordGenerator.Punctuation = seed.Punctuation
+	menu.timerTestWordGenerator.PunctuationProfile = punctuationProfile(seed)
+	menu.timerTestWordGenerator.Numbers = seed.Numbers
+	menu.timerTestWordGenerator.Symbols = seed.Symbols
+	menu.timerTestWordGenerator.Frequency = seed.Frequency
+	menu.timerTestWordGenerator.KeyFilter = seed.KeyFilter
+	menu.timerTestWordGenerator.Seed(seed.Seed)
+	text := menu.timerTestWordGenerator.StartStream(seed.List)
 	return &TimerTestHandler{
 		BaseStateHandler: NewBaseStateHandler(StateTimerTest),
 		timer: Timer{
@@ -29,33 +56,50 @@ func NewTimerTestHandler(menu MainMenuHandler) *TimerTestHandler {
 			timedout:  false,
 		},
 		base: TestBase{
-			wordsToEnter:  menu.timerTestWordGenerator.Generate("Common words"),
+			wordsToEnter:  text,
 			inputBuffer:   make([]rune, 0),
 			rawInputCount: 0,
 			mistakes: mistakes{
 				mistakesAt:     make(map[int]bool, 0),
 				rawMistakesCnt: 0,
 			},
-			cursor:   0,
-			mainMenu: menu,
+			cursor:    0,
+			mainMenu:  menu,
+			wordInput: menu.currentUser.Config.WordInput,
+			failRules: newFailureRules(menu.currentUser.Config),
 		},
 		completed: false,
+		seed:      seed,
+		retrySeed: true,
 	}
 }
 
 func (h *TimerTestHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	if cmd, handled := handlePause(msg, &h.timer); handled {
+		return h, cmd
+	}
+	h.base.movePace(h.timer.Elapsed())
+
 	var commands []tea.Cmd
 	switch msg := msg.(type) {
 	case timer.TickMsg:
+		if h.timer.Paused() {
+			break
+		}
 		timerUpdate, cmdUpdate := h.timer.timer.Update(msg)
 		h.timer.timer = timerUpdate
 		commands = append(commands, cmdUpdate)
+		h.extendText()
 
 		elapsedSeconds := h.timer.Elapsed().Seconds()
 		if int(elapsedSeconds) > len(h.base.wpmEachSecond) {
 			elapsedMinutes := elapsedSeconds / 60.0
 			if elapsedMinutes > 0 {
 				h.base.wpmEachSecond = append(h.base.wpmEachSecond, h.base.calculateNormalizedWpm(elapsedMinutes))
+				if h.base.checkSecond() {
+					results := h.calculateResults(context.model, context)
+					return &results, tea.Batch(commands...)
+				}
 			}
 		}
 
@@ -66,6 +110,9 @@ func (h *TimerTestHandler) HandleInput(msg tea.Msg, context *StateContext) (Stat
 			return &results, tea.Batch(commands...)
 		}
 
+	case tea.PasteMsg:
+		notePaste(msg, &h.base)
+
 	case tea.KeyPressMsg:
 		switch msg.String() {
 		case "esc":
@@ -82,6 +129,9 @@ func (h *TimerTestHandler) HandleInput(msg tea.Msg, context *StateContext) (Stat
 		case "ctrl+q":
 			return NewMainMenuHandler(context.model.session.User, context.model), nil
 		case "ctrl+r":
+			if h.retrySeed {
+				return NewSeededTestHandler(h.base.mainMenu, h.seed, h.base.pace.ghost), nil
+			}
 			return NewTimerTestHandler(h.base.mainMenu), nil
 		default:
 			if len(msg.Text) > 0 || msg.String() == "space" {
@@ -89,10 +139,17 @@ func (h *TimerTestHandler) HandleInput(msg tea.Msg, context *StateContext) (Stat
 					h.timer.startTime = time.Now()
 					commands = append(commands, h.timer.timer.Init())
 					h.timer.isRunning = true
+					startPace(context, &h.base, "timer", h.seed.Value)
 				}
 
+				mistakesBefore := h.base.mistakes.rawMistakesCnt
 				handleCharacterInputFromMsg(msg, &h.base)
 				recordInput(msg, &h.base, h.timer.Elapsed().Milliseconds())
+				if h.base.checkKeystroke(mistakesBefore) {
+					results := h.calculateResults(context.model, context)
+					return &results, tea.Batch(commands...)
+				}
+				h.extendText()
 			}
 		}
 	}
@@ -100,15 +157,35 @@ func (h *TimerTestHandler) HandleInput(msg tea.Msg, context *StateContext) (Stat
 	return h, tea.Batch(commands...)
 }
 
+// extendText keeps the text ahead of the cursor, and of a ghost being raced,
+// however long the test runs.
+func (h *TimerTestHandler) extendText() {
+	if len(h.base.wordsToEnter)-max(h.base.cursor, h.base.pace.cursor) > streamLookahead {
+		return
+	}
+	more := h.base.mainMenu.timerTestWordGenerator.ExtendStream(streamChunk)
+	h.base.wordsToEnter = append(h.base.wordsToEnter, more...)
+}
+
 func (h *TimerTestHandler) Render(m *model) string {
 	termWidth, termHeight := m.width-2, m.height-2
 
 	timer := style(h.timer.timer.View(), m.styles.themeFunc)
+	timer += pausedLabel(&h.timer, m.styles)
+	if label := h.seed.KeyFilter.Label(); label != "" {
+		timer += "  " + style(label, m.styles.toEnter)
+	}
+	if h.base.pace.ghost != nil {
+		timer += "  " + style("racing ghost", m.styles.toEnter)
+	}
+	if h.base.mainMenu.currentUser.Config.LiveStats {
+		timer += "\n" + h.base.renderLiveStats(h.timer.Elapsed(), m.styles)
+	}
 	s := ""
 
 	paragraph := h.base.renderParagraph(lineLenLimit, m.styles)
 	lines := strings.Split(paragraph, "\n")
-	cursorLine := findCursorLine(lines, h.base.cursor)
+	cursorLine := findCursorLine(lines, h.base.displayCursor())
 
 	linesAroundCursor := strings.Join(getLinesAroundCursor(lines, cursorLine), "\n")
 
@@ -137,15 +214,33 @@ func (h *TimerTestHandler) ValidateTransition(to StateType, context *StateContex
 }
 
 func (test TimerTestHandler) calculateResults(m *model, context *StateContext) ResultsHandler {
-	elapsedMinutes := test.timer.duration.Minutes()
+	test.base.integrity = checkIntegrity(test.base)
+	// A failed test ends before the timer runs out.
+	elapsed := test.timer.duration
+	if test.base.failed != "" {
+		elapsed = test.timer.Elapsed()
+	}
+	elapsedMinutes := elapsed.Minutes()
 	wpm := test.base.calculateNormalizedWpm(elapsedMinutes)
 	wpmChart := NewWPMChartBubble(m.width/2, m.height/2)
 	wpmChart.UpdateData(test.base.wpmEachSecond)
 
 	accuracy := test.base.calculateAccuracy()
-	isPunctuation := test.base.mainMenu.currentUser.Config.Punctuation
+	chars := test.base.characterStats()
 
-	saveTestResult(context, "timer", int(test.timer.duration.Seconds()), test.timer.duration.Seconds(), wpm, accuracy, isPunctuation, test.base.rawInputCount, test.base.mistakes.rawMistakesCnt)
+	testID := saveTestResult(context, testOutcome{
+		testType:  "timer",
+		testValue: int(test.timer.duration.Seconds()),
+		duration:  elapsed,
+		wpm:       wpm,
+		accuracy:  accuracy,
+		seed:      &test.seed,
+		base:      test.base,
+	})
+	saveBigramStats(context, testID, test.base)
+	saveMissedWords(context, testID, test.base)
+	saveKeyStats(context, testID, test.base)
+	saveReplay(context, testID, test.seed.String(), test.base)
 
 	return ResultsHandler{
 		testType:      "timer",
@@ -153,15 +248,22 @@ func (test TimerTestHandler) calculateResults(m *model, context *StateContext) R
 		accuracy:      accuracy,
 		rawWpm:        int(test.base.calculateRawWpm(elapsedMinutes)),
 		cpm:           test.base.calculateCpm(elapsedMinutes),
-		time:          test.timer.duration,
+		chars:         chars,
+		time:          elapsed,
 		test:          test.base,
 		wpmEachSecond: test.base.wpmEachSecond,
 		mainMenu:      test.base.mainMenu,
+		seed:          test.seed.String(),
+		retrySeed:     test.retrySeed,
+		raceResult:    raceResult(test.base),
 		resultsSelection: []string{
 			"Next Test",
 			"Main Menu",
 			"Replay",
+			"Practice Mistakes",
+			"Race Ghost",
 		},
 		wpmChart: wpmChart,
+		heatmap:  testHeatmap(test.base),
 	}
 }
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"termtyper/database"
+	"termtyper/words"
+
+	"charm.land/bubbles/v2/timer"
+	"github.com/muesli/termenv"
+)
+
+func TestTimerTestExtendsText(t *testing.T) {
+	menu := MainMenuHandler{
+		timerTestWordGenerator: words.NewGenerator(),
+		currentUser:            &database.ApplicationUser{Config: &database.UserConfig{Time: 1440}},
+	}
+	seed, err := ParseSeedCode("t1440p-en-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewSeededTimerTestHandler(menu, seed)
+
+	// Type far past the initial 300 words, as an endurance session would.
+	for i := 0; i < 20000; i++ {
+		handleCharacterInputFromRune(h.base.wordsToEnter[h.base.cursor], &h.base)
+		h.extendText()
+	}
+
+	if len(h.base.inputBuffer) != 20000 {
+		t.Fatalf("input should never be dropped, typed %d of 20000", len(h.base.inputBuffer))
+	}
+	if remaining := len(h.base.wordsToEnter) - h.base.cursor; remaining < streamLookahead {
+		t.Errorf("expected at least %d runes ahead of the cursor, got %d", streamLookahead, remaining)
+	}
+	if len(h.base.mistakes.mistakesAt) != 0 {
+		t.Errorf("typing the text exactly should not record mistakes")
+	}
+
+	again := NewSeededTimerTestHandler(menu, seed)
+	for len(again.base.wordsToEnter) < len(h.base.wordsToEnter) {
+		again.base.cursor = len(again.base.wordsToEnter)
+		again.extendText()
+	}
+	if !strings.HasPrefix(string(again.base.wordsToEnter), string(h.base.wordsToEnter)) {
+		t.Error("an extended seeded text should not depend on when it was extended")
+	}
+}
+
+func TestTimerTestKeyFilter(t *testing.T) {
+	menu := MainMenuHandler{
+		timerTestWordGenerator: words.NewGenerator(),
+		currentUser:            &database.ApplicationUser{Config: &database.UserConfig{Time: 30}},
+	}
+	seed, err := ParseSeedCode("t30kh-en-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewSeededTimerTestHandler(menu, seed)
+
+	for _, word := range strings.Fields(string(h.base.wordsToEnter)) {
+		if !seed.KeyFilter.Allows(word) {
+			t.Fatalf("%q is not typeable on the home row", word)
+		}
+	}
+
+	m := &model{width: 120, height: 40, styles: createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")}
+	if view := h.Render(m); !strings.Contains(view, "home row") {
+		t.Errorf("expected the test screen to show the key filter")
+	}
+}
+
+func TestTimerTestMovesPaceOnInputNotRender(t *testing.T) {
+	menu := MainMenuHandler{
+		timerTestWordGenerator: words.NewGenerator(),
+		currentUser:            &database.ApplicationUser{Config: &database.UserConfig{Time: 30}},
+	}
+	seed, err := ParseSeedCode("t30-en-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewSeededTimerTestHandler(menu, seed)
+	h.base.pace.wpm = 60
+	h.timer.isRunning = true
+	h.timer.startTime = time.Now().Add(-2 * time.Second)
+
+	m := &model{width: 120, height: 40, styles: createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")}
+	h.Render(m)
+	if h.base.pace.cursor != 0 {
+		t.Fatalf("rendering should not move the pace caret, got %d", h.base.pace.cursor)
+	}
+
+	h.HandleInput(timer.TickMsg{}, &StateContext{model: m})
+	if h.base.pace.cursor < 10 {
+		t.Errorf("expected a tick to move the pace caret two seconds in, got %d", h.base.pace.cursor)
+	}
+}
@@ -1,7 +1,22 @@
 package cmd
 
 import (
+	"unicode"
+
 	tea "charm.land/bubbletea/v2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// skippedRune stands in the input buffer for a letter that was skipped by
+	// pressing space early.
+	skippedRune = '\x00'
+	// maxExtraLetters caps how far past its end a word can be typed.
+	maxExtraLetters = 20
+	// pasteMinRunes is how long the text of a single key press has to be to
+	// count as pasted. It is a burst on its own, and input methods commit
+	// less than that at once.
+	pasteMinRunes = burstKeys
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -28,26 +43,41 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func handleBackspace(base *TestBase) {
-	if len(base.mistakes.mistakesAt) == 0 && len(base.wordsToEnter) > 0 {
+	if !base.hasMistakes() && len(base.wordsToEnter) > 0 {
+		return
+	}
+
+	position := len(base.inputBuffer)
+	if extra := base.extra[position]; len(extra) > 0 {
+		base.extra[position] = extra[:len(extra)-1]
+		if len(base.extra[position]) == 0 {
+			delete(base.extra, position)
+		}
 		return
 	}
 
 	base.inputBuffer = deleteLastChar(base.inputBuffer)
 	inputLength := len(base.inputBuffer)
-	base.rawInputCount -= 1
 	_, ok := base.mistakes.mistakesAt[inputLength]
 
 	if ok {
 		delete(base.mistakes.mistakesAt, inputLength)
 	}
 
+	// Going back over a skip lands right after the last letter typed.
+	for inputLength > 0 && base.inputBuffer[inputLength-1] == skippedRune {
+		base.inputBuffer = base.inputBuffer[:inputLength-1]
+		inputLength--
+		delete(base.mistakes.mistakesAt, inputLength)
+	}
+
 	base.cursor = inputLength
 }
 
 func handleCtrlBackspace(base *TestBase) {
 	//TODO: Fix this
 	//TODO if multiple punctuation is in a row we delete the punctuations
-	if len(base.mistakes.mistakesAt) == 0 && len(base.wordsToEnter) > 0 {
+	if !base.hasMistakes() && len(base.wordsToEnter) > 0 {
 		return
 	}
 
@@ -69,37 +99,55 @@ func handleCtrlBackspace(base *TestBase) {
 
 	base.inputBuffer = base.inputBuffer[0 : len(base.inputBuffer)-(charToDelete)]
 	base.cursor = base.cursor - charToDelete
-}
 
-func handleCharacterInputFromMsg(msg tea.KeyPressMsg, base *TestBase) {
-	// TODO: Fix this. Doesn't work, I can still paste into input buffer.
-	if len(msg.Text) > 1 {
-		return
+	for position := range base.extra {
+		if position >= len(base.inputBuffer) {
+			delete(base.extra, position)
+		}
 	}
+}
 
-	if len(base.inputBuffer) == len(base.wordsToEnter) {
-		return
+// inputRunes returns the characters a key press typed, composed to NFC so
+// that an accent sent as a separate combining mark lands on its letter. Input
+// methods and dead keys send several characters in one press, which are typed
+// in turn. Enter and tab type a newline and a tab.
+func inputRunes(msg tea.KeyPressMsg) []rune {
+	switch msg.String() {
+	case "enter":
+		return []rune{'\n'}
+	case "tab":
+		return []rune{'\t'}
 	}
-	inputLetter := []rune(msg.Text)[len([]rune(msg.Text))-1]
-	currInputBufferLen := len(base.inputBuffer)
-	correctNextLetter := base.wordsToEnter[currInputBufferLen]
+	return []rune(norm.NFC.String(msg.Text))
+}
 
-	base.inputBuffer = append(base.inputBuffer, inputLetter)
-	base.rawInputCount += 1
+// isPastedText reports whether a key press carries more text than a press
+// types, which is pasted text from terminals without bracketed paste.
+func isPastedText(input []rune) bool {
+	return len(input) >= pasteMinRunes
+}
 
-	if inputLetter != correctNextLetter {
-		base.mistakes.mistakesAt[currInputBufferLen] = true
-		base.mistakes.rawMistakesCnt = base.mistakes.rawMistakesCnt + 1
+// handleCharacterInputFromMsg types the characters of a key press. Pasted text
+// is dropped and counted.
+func handleCharacterInputFromMsg(msg tea.KeyPressMsg, base *TestBase) {
+	input := inputRunes(msg)
+	if isPastedText(input) {
+		base.pasteAttempts++
+		return
 	}
 
-	newCursorPosition := len(base.inputBuffer)
-	base.cursor = newCursorPosition
+	for _, inputLetter := range input {
+		handleCharacterInputFromRune(inputLetter, base)
+	}
 }
 
 func handleCharacterInputFromRune(char rune, base *TestBase) {
 	if len(base.inputBuffer) == len(base.wordsToEnter) {
 		return
 	}
+	if base.wordInput && handleWordInput(char, base) {
+		return
+	}
 	currInputBufferLen := len(base.inputBuffer)
 	correctNextLetter := base.wordsToEnter[currInputBufferLen]
 
@@ -115,30 +163,85 @@ func handleCharacterInputFromRune(char rune, base *TestBase) {
 	base.cursor = newCursorPosition
 }
 
+// handleWordInput applies the word-based input rules and reports whether it
+// has dealt with the key. A space in the middle of a word fills the rest of it
+// with skippedRune, marked as mistakes, and then types the separator. A space
+// before anything of the word has been typed, or in the last word where there
+// is nothing to skip to, is ignored. Letters typed where the word has ended go
+// to extra.
+func handleWordInput(char rune, base *TestBase) bool {
+	position := len(base.inputBuffer)
+	expected := base.wordsToEnter[position]
+
+	if unicode.IsSpace(expected) && !unicode.IsSpace(char) {
+		if len(base.extra[position]) < maxExtraLetters {
+			if base.extra == nil {
+				base.extra = make(map[int][]rune)
+			}
+			base.extra[position] = append(base.extra[position], char)
+		}
+		base.rawInputCount += 1
+		base.mistakes.rawMistakesCnt = base.mistakes.rawMistakesCnt + 1
+		return true
+	}
+
+	if char != ' ' || unicode.IsSpace(expected) {
+		return false
+	}
+	if position == 0 || unicode.IsSpace(base.wordsToEnter[position-1]) {
+		return true
+	}
+
+	end := position
+	for end < len(base.wordsToEnter) && !unicode.IsSpace(base.wordsToEnter[end]) {
+		end++
+	}
+	if end == len(base.wordsToEnter) {
+		return true
+	}
+
+	for i := position; i < end; i++ {
+		base.inputBuffer = append(base.inputBuffer, skippedRune)
+		base.mistakes.mistakesAt[i] = true
+	}
+	base.mistakes.rawMistakesCnt = base.mistakes.rawMistakesCnt + 1
+	return false
+}
+
+// hasMistakes reports whether anything typed is wrong, which is when
+// backspace is allowed.
+func (base *TestBase) hasMistakes() bool {
+	return len(base.mistakes.mistakesAt) > 0 || len(base.extra) > 0
+}
+
 func handleCharacterInputZenMode(msg tea.KeyPressMsg, base *TestBase) {
-	inputLetter := []rune(msg.Text)[len([]rune(msg.Text))-1]
-	base.inputBuffer = append(base.inputBuffer, inputLetter)
-	base.rawInputCount += 1
+	input := inputRunes(msg)
+	if isPastedText(input) {
+		return
+	}
+	base.inputBuffer = append(base.inputBuffer, input...)
+	base.rawInputCount += len(input)
 
 	newCursorPosition := len(base.inputBuffer)
 	base.cursor = newCursorPosition
 }
 
 func recordInput(msg tea.KeyPressMsg, base *TestBase, timestamp int64) {
-	var keyPress KeyPress
 	if msg.String() == "backspace" {
-		keyPress = KeyPress{
-			key:       '\b',
-			timestamp: timestamp,
-		}
-	} else {
-		keyPress = KeyPress{
-			key:       []rune(msg.Text)[len([]rune(msg.Text))-1],
-			timestamp: timestamp,
-		}
+		recordInputBackspace(base, timestamp)
+		return
 	}
 
-	base.testRecord = append(base.testRecord, keyPress)
+	input := inputRunes(msg)
+	if isPastedText(input) {
+		return
+	}
+	for _, key := range input {
+		base.testRecord = append(base.testRecord, KeyPress{
+			key:       key,
+			timestamp: timestamp,
+		})
+	}
 }
 
 func recordInputBackspace(base *TestBase, timestamp int64) {
@@ -148,3 +251,56 @@ func recordInputBackspace(base *TestBase, timestamp int64) {
 	}
 	base.testRecord = append(base.testRecord, keyPress)
 }
+
+// typedKey is a press from a test's key record that typed a character of its
+// text, as seen when the record is replayed.
+type typedKey struct {
+	press    KeyPress
+	position int
+	expected rune
+	// sinceLast is how long after the previous typed key the press came. It is
+	// only meaningful when timed, which the first press and presses right
+	// after a backspace aren't.
+	sinceLast int64
+	timed     bool
+}
+
+// walkKeyRecord replays a test's key presses over its text and calls visit for
+// every press that typed a character of it, before the press is applied.
+// Backspaces are replayed but not visited, and presses past the end of the
+// text are dropped.
+func walkKeyRecord(base TestBase, visit func(key typedKey)) {
+	replay := TestBase{
+		wordsToEnter: base.wordsToEnter,
+		mistakes:     mistakes{mistakesAt: make(map[int]bool)},
+		wordInput:    base.wordInput,
+	}
+
+	var lastTimestamp int64
+	typedForward := false
+
+	for _, press := range base.testRecord {
+		if press.key == '\b' {
+			handleBackspace(&replay)
+			typedForward = false
+			continue
+		}
+
+		position := len(replay.inputBuffer)
+		if position >= len(replay.wordsToEnter) {
+			continue
+		}
+
+		visit(typedKey{
+			press:     press,
+			position:  position,
+			expected:  replay.wordsToEnter[position],
+			sinceLast: press.timestamp - lastTimestamp,
+			timed:     typedForward,
+		})
+
+		handleCharacterInputFromRune(press.key, &replay)
+		lastTimestamp = press.timestamp
+		typedForward = true
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func newTestBase(text string) TestBase {
+	return TestBase{
+		wordsToEnter: []rune(text),
+		inputBuffer:  make([]rune, 0),
+		mistakes: mistakes{
+			mistakesAt: make(map[int]bool),
+		},
+	}
+}
+
+func typeText(base *TestBase, text string) {
+	for _, r := range text {
+		handleCharacterInputFromMsg(tea.KeyPressMsg{Code: r, Text: string(r)}, base)
+	}
+}
+
+func TestHandleCharacterInputUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		typed    string
+		mistakes int
+	}{
+		{name: "accented latin", expected: "café crème", typed: "café crème", mistakes: 0},
+		{name: "polish", expected: "źle żaden", typed: "źle zaden", mistakes: 1},
+		{name: "cyrillic", expected: "жизнь", typed: "жизнь", mistakes: 0},
+		{name: "german", expected: "Straße grün", typed: "Strasse", mistakes: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newTestBase(tt.expected)
+			typeText(&base, tt.typed)
+
+			if len(base.inputBuffer) != len([]rune(tt.typed)) {
+				t.Errorf("expected %d runes in the input buffer, got %d", len([]rune(tt.typed)), len(base.inputBuffer))
+			}
+			if len(base.mistakes.mistakesAt) != tt.mistakes {
+				t.Errorf("expected %d mistakes, got %d", tt.mistakes, len(base.mistakes.mistakesAt))
+			}
+			if base.cursor != len(base.inputBuffer) {
+				t.Errorf("cursor should follow the input buffer, got %d", base.cursor)
+			}
+		})
+	}
+}
+
+func TestHandleCharacterInputIgnoresPastedText(t *testing.T) {
+	base := newTestBase("hello world")
+	handleCharacterInputFromMsg(tea.KeyPressMsg{Text: "hello world"}, &base)
+
+	if len(base.inputBuffer) != 0 {
+		t.Errorf("pasted text should not be typed, got %q", string(base.inputBuffer))
+	}
+	if base.pasteAttempts != 1 {
+		t.Errorf("expected the paste to be counted, got %d", base.pasteAttempts)
+	}
+}
+
+func TestHandleCharacterInputComposesMultiRuneText(t *testing.T) {
+	base := newTestBase("café 東京")
+	// A dead key sends the accent as a combining mark after its letter, and
+	// an input method commits several characters in one press.
+	for _, text := range []string{"c", "a", "f", "e\u0301", " ", "東京"} {
+		msg := tea.KeyPressMsg{Text: text}
+		handleCharacterInputFromMsg(msg, &base)
+		recordInput(msg, &base, 0)
+	}
+
+	if got := string(base.inputBuffer); got != "café 東京" {
+		t.Errorf("expected the text to be typed composed, got %q", got)
+	}
+	if base.hasMistakes() || base.pasteAttempts != 0 {
+		t.Errorf("expected no mistakes or pastes, got %d mistakes and %d pastes", base.mistakes.rawMistakesCnt, base.pasteAttempts)
+	}
+	if len(base.testRecord) != len([]rune("café 東京")) {
+		t.Errorf("expected a key record entry per character, got %d", len(base.testRecord))
+	}
+}
+
+func TestWordInput(t *testing.T) {
+	base := newTestBase("the quick fox")
+	base.wordInput = true
+	typeText(&base, "th quicker")
+
+	if got := string(base.inputBuffer); got != "th\x00 quick" {
+		t.Errorf("expected the skipped e to be filled in, got %q", got)
+	}
+	if got := string(base.extra[9]); got != "er" {
+		t.Errorf("expected er as extra letters, got %q", got)
+	}
+	if !base.mistakes.mistakesAt[2] || len(base.mistakes.mistakesAt) != 1 {
+		t.Errorf("expected only the skipped letter as a mistake, got %v", base.mistakes.mistakesAt)
+	}
+
+	chars := base.characterStats()
+	if chars.correct != 7 || chars.missed != 1 || chars.extra != 2 {
+		t.Errorf("expected 7 correct, 1 missed and 2 extra characters, got %+v", chars)
+	}
+
+	handleBackspace(&base)
+	handleBackspace(&base)
+	if len(base.extra) != 0 || base.cursor != 9 {
+		t.Errorf("backspace should remove the extra letters first, got %v at %d", base.extra, base.cursor)
+	}
+}
+
+func TestWordInputBackspaceOverSkip(t *testing.T) {
+	base := newTestBase("the fox")
+	base.wordInput = true
+	typeText(&base, " th ")
+
+	if got := string(base.inputBuffer); got != "th\x00 " {
+		t.Fatalf("a space before the word is started should be ignored, got %q", got)
+	}
+
+	handleBackspace(&base)
+	if got := string(base.inputBuffer); got != "th" || base.cursor != 2 || len(base.mistakes.mistakesAt) != 0 {
+		t.Errorf("backspace should go back to the last typed letter, got %q at %d", got, base.cursor)
+	}
+}
+
+func TestWordInputLastWord(t *testing.T) {
+	base := newTestBase("ab cd")
+	base.wordInput = true
+	typeText(&base, "ab c ")
+
+	if got := string(base.inputBuffer); got != "ab c" {
+		t.Errorf("space in the last word has nothing to skip to, got %q", got)
+	}
+}
+
+func TestWalkKeyRecord(t *testing.T) {
+	base := newTestBase("ab")
+	base.testRecord = []KeyPress{
+		{key: 'a', timestamp: 100},
+		{key: 'x', timestamp: 250},
+		{key: '\b', timestamp: 300},
+		{key: 'b', timestamp: 400},
+		{key: 'c', timestamp: 500},
+	}
+
+	var visited []typedKey
+	walkKeyRecord(base, func(typed typedKey) {
+		visited = append(visited, typed)
+	})
+
+	want := []typedKey{
+		{press: base.testRecord[0], position: 0, expected: 'a', sinceLast: 100, timed: false},
+		{press: base.testRecord[1], position: 1, expected: 'b', sinceLast: 150, timed: true},
+		{press: base.testRecord[3], position: 1, expected: 'b', sinceLast: 150, timed: false},
+	}
+	if len(visited) != len(want) {
+		t.Fatalf("visited %d keys, want %d: %+v", len(visited), len(want), visited)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf("key %d = %+v, want %+v", i, visited[i], want[i])
+		}
+	}
+}
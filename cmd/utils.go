@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"termtyper/database"
+	"time"
 )
 
 func mapToKeysSlice(mp map[int]bool) []int {
@@ -114,6 +115,24 @@ func UserConfigToMap(config *database.UserConfig) map[string]interface{} {
 	result["words"] = config.Words
 	result["punctuation"] = config.Punctuation
 	result["theme"] = config.Theme
+	result["quote_length"] = config.QuoteLength
+	result["word_list"] = config.WordList
+	result["language"] = config.Language
+	result["code_language"] = config.CodeLanguage
+	result["frequency"] = config.Frequency
+	result["punctuation_profile"] = config.PunctuationProfile
+	result["punctuation_weights"] = config.PunctuationWeights
+	result["numbers"] = config.Numbers
+	result["symbols"] = config.Symbols
+	result["key_filter"] = config.KeyFilter
+	result["key_filter_keys"] = config.KeyFilterKeys
+	result["word_input"] = config.WordInput
+	result["fail_mode"] = config.FailMode
+	result["min_wpm"] = config.MinWpm
+	result["min_accuracy"] = config.MinAccuracy
+	result["pace_caret"] = config.PaceCaret
+	result["pace_wpm"] = config.PaceWpm
+	result["live_stats"] = config.LiveStats
 
 	if config.CustomSettings != nil {
 		result["custom_settings"] = config.CustomSettings
@@ -146,24 +165,55 @@ func convertToInt(val interface{}) (int, error) {
 	}
 }
 
-func saveTestResult(context *StateContext, testType string, testValue int, duration float64, wpm float64, accuracy float64, isPunctuation bool, rawInputCount int, mistakesCount int) {
+// testOutcome is what a finished test saves to its history. Counts,
+// integrity and failure are read from base; seed is nil for tests that
+// don't come from a seed code.
+type testOutcome struct {
+	testType  string
+	testValue int
+	duration  time.Duration
+	wpm       float64
+	accuracy  float64
+	seed      *SeedCode
+	base      TestBase
+}
+
+func saveTestResult(context *StateContext, outcome testOutcome) int64 {
 	userID := context.model.session.User.Id
-	if userID <= 0 {
-		return
+	base := outcome.base
+	if userID <= 0 || base.integrity.void {
+		return 0
 	}
 
+	chars := base.characterStats()
 	record := &database.TestRecord{
-		UserID:        userID,
-		TestType:      testType,
-		TestValue:     testValue,
-		Duration:      duration,
-		WPM:          wpm,
-		WordsTyped:    rawInputCount / 5,
-		Accuracy:      accuracy,
-		IsPunctuation: isPunctuation,
-		RawChars:      rawInputCount,
-		MistakesCount: mistakesCount,
-	}
-
-	_ = database.SaveTestResult(context.model.context.UserRepository, record)
+		UserID:         userID,
+		TestType:       outcome.testType,
+		TestValue:      outcome.testValue,
+		Duration:       outcome.duration.Seconds(),
+		WPM:            outcome.wpm,
+		WordsTyped:     base.rawInputCount / 5,
+		Accuracy:       outcome.accuracy,
+		RawChars:       base.rawInputCount,
+		MistakesCount:  base.mistakes.rawMistakesCnt,
+		CorrectChars:   chars.correct,
+		IncorrectChars: chars.incorrect,
+		ExtraChars:     chars.extra,
+		MissedChars:    chars.missed,
+		Flagged:        base.integrity.reason,
+		Failed:         base.failed != "",
+	}
+	if seed := outcome.seed; seed != nil {
+		record.IsPunctuation = seed.Punctuation
+		record.Numbers = seed.Numbers
+		record.Symbols = seed.Symbols
+		record.KeyFilter = seed.KeyFilter.String()
+		record.Seed = seed.String()
+	}
+
+	if err := database.SaveTestResult(context.model.context.UserRepository, record); err != nil {
+		return 0
+	}
+
+	return record.ID
 }
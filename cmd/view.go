@@ -4,16 +4,16 @@ import (
 	"fmt"
 	"math"
 	"regexp"
-	"sort"
 	"strings"
+	"unicode/utf8"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/muesli/reflow/indent"
-	"github.com/muesli/reflow/wordwrap"
 )
 
 var lineLenLimit int
+var tabWidth int = 4
 var minLineLen int = 5
 var maxLineLen int = 40
 var resultsStyle = lipgloss.NewStyle().
@@ -33,16 +33,35 @@ func (m model) View() tea.View {
 
 	v := tea.NewView(m.stateMachine.Render())
 	v.AltScreen = true
+	v.ReportFocus = true
 	return v
 }
 
 func (base *TestBase) renderParagraph(lineLimit int, styles Styles) string {
+	wrappedParagraph := wrapParagraph(base.renderText(styles), lineLimit)
+	return wrappedParagraph
+}
+
+func (base *TestBase) renderText(styles Styles) string {
 	paragraph := base.renderInput(styles)
 	paragraph += base.renderCursor(styles)
 	paragraph += base.renderWordsToEnter(styles)
 
-	wrappedParagraph := wrapParagraph(paragraph, lineLimit)
-	return wrappedParagraph
+	return paragraph
+}
+
+// renderCodeLines renders multi-line text without wrapping, so every line of
+// the source stays on its own line. Returns the lines and the cursor's line.
+func (base *TestBase) renderCodeLines(styles Styles) ([]string, int) {
+	lines := strings.Split(base.renderText(styles), "\n")
+	cursorLine := 0
+	for _, r := range base.wordsToEnter[:base.cursor] {
+		if r == '\n' {
+			cursorLine++
+		}
+	}
+
+	return lines, cursorLine
 }
 
 func (base *TestBase) renderParagraphZenMode(lineLimit int, styles Styles) string {
@@ -66,55 +85,82 @@ func (base *TestBase) renderInputZenMode(styles Styles) string {
 }
 
 func (base *TestBase) renderInput(styles Styles) string {
-	mistakes := mapToKeysSlice(base.mistakes.mistakesAt)
-	sort.Ints(mistakes)
-
 	var input strings.Builder
 
-	if len(mistakes) == 0 {
-		input.WriteString(styleAll(base.inputBuffer, styles.correct))
-	} else {
-		previousMistake := -1
-
-		for _, mistakeAt := range mistakes {
-			sliceUntilMistake := base.inputBuffer[previousMistake+1 : mistakeAt]
+	runStart := 0
+	for i := range base.inputBuffer {
+		extra := base.extra[i]
+		mistake := base.mistakes.mistakesAt[i]
+		paced := base.paceAt(i)
+		if !mistake && !paced && len(extra) == 0 {
+			continue
+		}
 
-			var mistakeSlice []rune
-			mistakeSlice = base.wordsToEnter[mistakeAt : mistakeAt+1]
-			if string(mistakeSlice) == " " {
-				mistakeSlice = base.inputBuffer[mistakeAt : mistakeAt+1]
+		input.WriteString(styleAll(base.inputBuffer[runStart:i], styles.correct))
+		input.WriteString(styleAll(extra, styles.extra))
+		runStart = i
+
+		if mistake || paced {
+			char, charStyle := base.inputBuffer[i], styles.correct
+			if mistake {
+				switch {
+				case base.inputBuffer[i] == skippedRune:
+					char, charStyle = base.wordsToEnter[i], styles.missed
+				case base.wordsToEnter[i] == ' ':
+					charStyle = styles.mistake
+				default:
+					char, charStyle = base.wordsToEnter[i], styles.mistake
+				}
 			}
-
-			input.WriteString(styleAll(sliceUntilMistake, styles.correct))
-			input.WriteString(style(string(mistakeSlice), styles.mistake))
-
-			previousMistake = mistakeAt
+			if paced {
+				charStyle = styles.pace
+			}
+			input.WriteString(styleMarked(char, charStyle))
+			runStart = i + 1
 		}
-
-		inputAfterLastMistake := base.inputBuffer[previousMistake+1:]
-		input.WriteString(styleAll(inputAfterLastMistake, styles.correct))
 	}
 
+	input.WriteString(styleAll(base.inputBuffer[runStart:], styles.correct))
+	input.WriteString(styleAll(base.extra[len(base.inputBuffer)], styles.extra))
+
 	return input.String()
 }
 
+// displayCursor is the cursor's position in the rendered text, which runs
+// ahead of the cursor by the extra letters typed before it.
+func (base *TestBase) displayCursor() int {
+	cursor := base.cursor
+	for position, extra := range base.extra {
+		if position <= base.cursor {
+			cursor += len(extra)
+		}
+	}
+	return cursor
+}
+
 func (base *TestBase) renderCursor(styles Styles) string {
 	if len(base.inputBuffer) == len(base.wordsToEnter) {
 		s := [1]rune{' '}
 		return style(string(s[:]), styles.cursor)
 	}
-	cursorLetter := base.wordsToEnter[len(base.inputBuffer) : len(base.inputBuffer)+1]
+	cursorLetter := base.wordsToEnter[len(base.inputBuffer)]
 
-	return style(string(cursorLetter), styles.cursor)
+	return styleMarked(cursorLetter, styles.cursor)
 }
 
 func (base *TestBase) renderWordsToEnter(styles Styles) string {
 	if len(base.inputBuffer) == len(base.wordsToEnter) {
 		return ""
 	}
-	wordsToEnter := base.wordsToEnter[len(base.inputBuffer)+1:]
+	start := len(base.inputBuffer) + 1
+	if base.paceAt(base.pace.cursor) && base.pace.cursor >= start {
+		return style(displayString(base.wordsToEnter[start:base.pace.cursor]), styles.toEnter) +
+			styleMarked(base.wordsToEnter[base.pace.cursor], styles.pace) +
+			style(displayString(base.wordsToEnter[base.pace.cursor+1:]), styles.toEnter)
+	}
+	wordsToEnter := base.wordsToEnter[start:]
 
-	return style(string(wordsToEnter), styles.toEnter)
+	return style(displayString(wordsToEnter), styles.toEnter)
 }
 
 func positionVertically(termHeight int) string {
@@ -133,18 +179,61 @@ func (m model) indent(block string, indentBy uint) string {
 	return indentedBlock
 }
 
+// wrapParagraph breaks styled text into lines of at most lineLimit visible
+// runes. Lines only break after a space, which stays at the end of its line,
+// and existing newlines are kept, so every rune of the text is preserved.
 func wrapParagraph(paragraph string, lineLimit int) string {
-	paragraph = strings.ReplaceAll(paragraph, " ", "💩")
+	var result, line, word strings.Builder
+	lineLen, wordLen := 0, 0
+	afterSpace := false
+
+	flushWord := func() {
+		if lineLen > 0 && lineLen+wordLen > lineLimit {
+			result.WriteString(line.String())
+			result.WriteRune('\n')
+			line.Reset()
+			lineLen = 0
+		}
+		line.WriteString(word.String())
+		lineLen += wordLen
+		word.Reset()
+		wordLen = 0
+	}
 
-	f := wordwrap.NewWriter(lineLimit)
-	f.Breakpoints = []rune{'💩'}
-	f.KeepNewlines = false
-	f.Write([]byte(paragraph))
-	f.Close()
+	for i := 0; i < len(paragraph); {
+		if paragraph[i] == '\x1b' {
+			if loc := ansiCode.FindStringIndex(paragraph[i:]); loc != nil && loc[0] == 0 {
+				word.WriteString(paragraph[i : i+loc[1]])
+				i += loc[1]
+				continue
+			}
+		}
 
-	paragraph = strings.ReplaceAll(f.String(), "💩", " ")
+		r, size := utf8.DecodeRuneInString(paragraph[i:])
+		i += size
+
+		if r == '\n' {
+			flushWord()
+			result.WriteString(line.String())
+			result.WriteRune('\n')
+			line.Reset()
+			lineLen = 0
+			afterSpace = false
+			continue
+		}
 
-	return paragraph
+		if afterSpace && r != ' ' {
+			flushWord()
+		}
+		word.WriteRune(r)
+		wordLen++
+		afterSpace = r == ' '
+	}
+
+	flushWord()
+	result.WriteString(line.String())
+
+	return result.String()
 }
 
 func wrapWithCursor(shouldWrap bool, line string, stringStyle StringStyle) string {
@@ -162,7 +251,7 @@ func findCursorLine(lines []string, cursorAt int) int {
 	cursorLine := 0
 
 	for _, line := range lines {
-		lineLen := len(dropAnsiCodes(line))
+		lineLen := utf8.RuneCountInString(dropAnsiCodes(line))
 
 		lenAcc += lineLen
 
@@ -198,16 +287,36 @@ func style(str string, style StringStyle) string {
 func styleAll(runes []rune, style StringStyle) string {
 	var acc strings.Builder
 
-	for idx, char := range runes {
-		_ = idx
-		acc.WriteString(style(string(char)).String())
+	for _, char := range runes {
+		if char == '\n' {
+			acc.WriteRune(char)
+			continue
+		}
+		acc.WriteString(style(displayString([]rune{char})).String())
 	}
 
 	return acc.String()
 }
 
-func dropAnsiCodes(colored string) string {
-	m := regexp.MustCompile("\x1b\\[[0-9;]*m")
+// styleMarked styles a single rune that must stay visible even when it is
+// whitespace, such as the cursor or a mistake on a tab or a newline.
+func styleMarked(char rune, stringStyle StringStyle) string {
+	switch char {
+	case '\n':
+		return style("↵", stringStyle) + "\n"
+	case '\t':
+		return style("→"+strings.Repeat(" ", tabWidth-1), stringStyle)
+	default:
+		return style(string(char), stringStyle)
+	}
+}
+
+func displayString(runes []rune) string {
+	return strings.ReplaceAll(string(runes), "\t", strings.Repeat(" ", tabWidth))
+}
 
-	return m.ReplaceAllString(colored, "")
+var ansiCode = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func dropAnsiCodes(colored string) string {
+	return ansiCode.ReplaceAllString(colored, "")
 }
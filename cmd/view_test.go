@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/muesli/termenv"
+)
+
+func TestFindCursorLineCountsRunes(t *testing.T) {
+	lines := []string{"źle żaden ", "жизнь это ", "grün"}
+
+	tests := []struct {
+		cursorAt int
+		expected int
+	}{
+		{cursorAt: 0, expected: 0},
+		{cursorAt: 9, expected: 0},
+		{cursorAt: 10, expected: 1},
+		{cursorAt: 19, expected: 1},
+		{cursorAt: 20, expected: 2},
+	}
+
+	for _, tt := range tests {
+		if got := findCursorLine(lines, tt.cursorAt); got != tt.expected {
+			t.Errorf("findCursorLine(%d) = %d, expected %d", tt.cursorAt, got, tt.expected)
+		}
+	}
+}
+
+func TestWrapParagraphKeepsEveryRune(t *testing.T) {
+	paragraph := "жизнь это дело źle żaden café crème grün"
+	wrapped := wrapParagraph(paragraph, 12)
+
+	if joined := strings.ReplaceAll(wrapped, "\n", ""); joined != paragraph {
+		t.Errorf("wrapping should only insert line breaks, got %q", joined)
+	}
+
+	lines := strings.Split(wrapped, "\n")
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > 12 {
+			t.Errorf("line %q is longer than the limit", line)
+		}
+	}
+	if len(lines) < 2 {
+		t.Errorf("expected the paragraph to wrap, got %q", wrapped)
+	}
+	if findCursorLine(lines, utf8.RuneCountInString(paragraph)-1) != len(lines)-1 {
+		t.Error("the last rune should be found on the last line")
+	}
+}
+
+func TestWrapParagraphStyledText(t *testing.T) {
+	styles := createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")
+	base := newTestBase("the quick brown fox jumps over the lazy dog")
+	typeText(&base, "the quick")
+
+	wrapped := wrapParagraph(base.renderText(styles), 12)
+	plain := dropAnsiCodes(wrapped)
+
+	expected := "the quick \nbrown fox \njumps over \nthe lazy dog"
+	if plain != expected {
+		t.Errorf("expected %q, got %q", expected, plain)
+	}
+}
+
+func TestWrapParagraphKeepsNewlines(t *testing.T) {
+	wrapped := wrapParagraph("if x {\n\treturn y\n}", 40)
+
+	if wrapped != "if x {\n\treturn y\n}" {
+		t.Errorf("newlines should be kept, got %q", wrapped)
+	}
+}
+
+func TestRenderCodeLines(t *testing.T) {
+	styles := createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")
+	base := newTestBase("if x {\n\treturn y\n}")
+	typeText(&base, "if x {\n\tret")
+
+	lines, cursorLine := base.renderCodeLines(styles)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), lines)
+	}
+	if cursorLine != 1 {
+		t.Errorf("expected the cursor on line 1, got %d", cursorLine)
+	}
+	if got := dropAnsiCodes(lines[1]); got != "    return y" {
+		t.Errorf("tabs should render as spaces, got %q", got)
+	}
+}
+
+func TestRenderWordInput(t *testing.T) {
+	styles := createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")
+	base := newTestBase("the quick fox")
+	base.wordInput = true
+	typeText(&base, "th quicker")
+
+	plain := dropAnsiCodes(base.renderText(styles))
+	if plain != "the quicker fox" {
+		t.Errorf("expected skipped and extra letters inline, got %q", plain)
+	}
+	if cursor := base.displayCursor(); cursor != 11 {
+		t.Errorf("expected the cursor after the extra letters, got %d", cursor)
+	}
+}
+
+func TestRenderPaceCaret(t *testing.T) {
+	styles := createStyles(termenv.ANSI256, termenv.ANSIWhite, "#FF00FF")
+	base := newTestBase("the quick fox")
+	base.pace.wpm = 60
+	typeText(&base, "the")
+
+	// 60 WPM is five characters a second, so after a second the pace caret
+	// is on the u, ahead of the cursor.
+	base.movePace(time.Second)
+	if base.pace.cursor != 5 {
+		t.Fatalf("expected the pace caret at 5, got %d", base.pace.cursor)
+	}
+	rendered := base.renderText(styles)
+	if !strings.Contains(rendered, style("u", styles.pace)) {
+		t.Errorf("expected the pace caret on the u, got %q", rendered)
+	}
+	if plain := dropAnsiCodes(rendered); plain != "the quick fox" {
+		t.Errorf("the pace caret shouldn't change the text, got %q", plain)
+	}
+
+	// Behind the cursor it is drawn over what was typed.
+	base.movePace(400 * time.Millisecond)
+	rendered = base.renderText(styles)
+	if !strings.Contains(rendered, style("e", styles.pace)) {
+		t.Errorf("expected the pace caret on the e, got %q", rendered)
+	}
+
+	base.pace.wpm = 0
+	base.movePace(time.Second)
+	if rendered := base.renderText(styles); strings.Contains(rendered, style("u", styles.pace)) {
+		t.Error("expected no pace caret without a pace")
+	}
+}
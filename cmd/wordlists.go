@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"termtyper/words"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	showLimit    int
+	importName   string
+	importOut    string
+	importRanked bool
+	importForce  bool
+
+	wordListsCmd = &cobra.Command{
+		Use:  "wordlists",
+		Long: "Inspect, validate and import word lists",
+	}
+	wordListsListCmd = &cobra.Command{
+		Use:  "list",
+		Long: "List the built-in and user word lists with their declared and real sizes",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tMETADATA NAME\tSIZE\tWORDS\tRANKED\tSOURCE")
+			for _, info := range words.ListInfos() {
+				source := "user"
+				if info.BuiltIn {
+					source = "built-in"
+				}
+				count := fmt.Sprint(info.Words)
+				if info.Size != 0 && info.Size != info.Words {
+					count += " (size mismatch)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%t\t%s\n", info.Name, info.MetaName, info.Size, count, info.Ranked, source)
+			}
+			return w.Flush()
+		},
+	}
+	wordListsShowCmd = &cobra.Command{
+		Use:  "show <name>",
+		Long: "Print a word list's metadata and words",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, ok := words.FindList(args[0])
+			if !ok {
+				return fmt.Errorf("no word list named %q, see `wordlists list`", args[0])
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "name:   %s\n", list.MetaData.Name)
+			fmt.Fprintf(out, "size:   %d\n", list.MetaData.Size)
+			fmt.Fprintf(out, "words:  %d\n", len(list.Words))
+			fmt.Fprintf(out, "ranked: %t\n\n", list.IsRanked())
+
+			shown := list.Words
+			if showLimit > 0 && showLimit < len(shown) {
+				shown = shown[:showLimit]
+			}
+			for i, word := range shown {
+				if len(list.MetaData.Counts) == len(list.Words) {
+					fmt.Fprintf(out, "%s\t%d\n", word, list.MetaData.Counts[i])
+				} else {
+					fmt.Fprintln(out, word)
+				}
+			}
+			if len(shown) < len(list.Words) {
+				fmt.Fprintf(out, "... %d more\n", len(list.Words)-len(shown))
+			}
+			return nil
+		},
+	}
+	wordListsValidateCmd = &cobra.Command{
+		Use:  "validate [file...]",
+		Long: "Check word list files for empty entries, duplicates, size mismatches and characters that can't be typed. Without arguments the built-in lists and every list in the --wordlists directory are checked.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			type target struct {
+				label string
+				list  words.WordList
+				err   error
+			}
+			var targets []target
+
+			paths := args
+			if len(paths) == 0 {
+				for _, lang := range words.Languages {
+					list, _ := words.FindList(lang.List)
+					targets = append(targets, target{label: lang.List + " (built-in)", list: list})
+				}
+				var err error
+				if paths, err = wordListFiles(wordListsDir); err != nil {
+					return err
+				}
+			}
+			for _, path := range paths {
+				list, err := words.ReadWordList(path)
+				targets = append(targets, target{label: path, list: list, err: err})
+			}
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for _, t := range targets {
+				problems := words.CheckWordList(t.list)
+				if t.err != nil {
+					problems = []error{t.err}
+				}
+				if len(problems) == 0 {
+					fmt.Fprintf(out, "ok    %s\n", t.label)
+					continue
+				}
+
+				failed++
+				fmt.Fprintf(out, "FAIL  %s\n", t.label)
+				for _, problem := range problems {
+					fmt.Fprintf(out, "      %v\n", problem)
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d word lists have problems", failed, len(targets))
+			}
+			return nil
+		},
+	}
+	wordListsImportCmd = &cobra.Command{
+		Use:  "import <file>",
+		Long: "Turn a plain text file into a word list in the JSON format TermTyper loads. Words are split on whitespace; repeats and words that can't be typed are dropped.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			path := args[0]
+			name := importName
+			if name == "" {
+				name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+			out := importOut
+			if out == "" {
+				out = filepath.Join(wordListsDir, name+".json")
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			list, skipped, err := words.ImportWordList(file, name, importRanked)
+			if err != nil {
+				return err
+			}
+
+			if err := writeWordList(out, list, importForce); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d words to %s", len(list.Words), out)
+			if skipped > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), " (skipped %d repeated or untypeable)", skipped)
+			}
+			fmt.Fprintln(cmd.OutOrStdout())
+			return nil
+		},
+	}
+)
+
+func init() {
+	wordListsShowCmd.Flags().IntVarP(&showLimit, "limit", "n", 0, "show at most this many words (0 for all)")
+	wordListsImportCmd.Flags().StringVar(&importName, "name", "", "name of the list (defaults to the file name)")
+	wordListsImportCmd.Flags().StringVarP(&importOut, "out", "o", "", "where to write the list (defaults to <wordlists>/<name>.json)")
+	wordListsImportCmd.Flags().BoolVar(&importRanked, "ranked", false, "the file is ordered from the most common word down")
+	wordListsImportCmd.Flags().BoolVarP(&importForce, "force", "f", false, "overwrite an existing file")
+
+	wordListsCmd.AddCommand(wordListsListCmd, wordListsShowCmd, wordListsValidateCmd, wordListsImportCmd)
+	RootCmd.AddCommand(wordListsCmd)
+}
+
+// wordListFiles returns the .json and .txt files in dir. A missing directory
+// has none.
+func wordListFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".json" || ext == ".txt") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths, nil
+}
+
+func writeWordList(path string, list words.WordList, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(list); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
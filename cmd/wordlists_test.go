@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runRoot(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	RootCmd.SetOut(&out)
+	RootCmd.SetErr(&out)
+	RootCmd.SetArgs(args)
+	defer RootCmd.SetArgs(nil)
+
+	err := RootCmd.Execute()
+	return out.String(), err
+}
+
+func TestWordListsImportAndValidate(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(t.TempDir(), "terms.txt")
+	if err := os.WriteFile(source, []byte("kernel shell\nshell pipe\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRoot(t, "--wordlists", dir, "wordlists", "import", source)
+	if err != nil {
+		t.Fatalf("import failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Wrote 3 words") || !strings.Contains(out, "skipped 1") {
+		t.Errorf("unexpected import output: %s", out)
+	}
+	if _, err := runRoot(t, "--wordlists", dir, "wordlists", "import", source); err == nil {
+		t.Error("expected a second import to refuse to overwrite the list")
+	}
+
+	if out, err := runRoot(t, "--wordlists", dir, "wordlists", "show", "terms"); err != nil || !strings.Contains(out, "kernel\nshell\npipe\n") {
+		t.Errorf("unexpected show output (%v): %s", err, out)
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"metadata": {"name": "broken", "size": 9}, "words": ["a", "a"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runRoot(t, "--wordlists", dir, "wordlists", "validate")
+	if err == nil {
+		t.Fatalf("expected validate to fail on broken.json:\n%s", out)
+	}
+	for _, want := range []string{"ok    Common words (built-in)", "ok    " + filepath.Join(dir, "terms.json"), "FAIL  " + broken, "declares 9 words but has 2", `repeats "a"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected validate output to contain %q:\n%s", want, out)
+		}
+	}
+}
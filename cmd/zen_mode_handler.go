@@ -36,6 +36,10 @@ func NewZenModeHandler(menu MainMenuHandler) *ZenModeHandler {
 }
 
 func (h *ZenModeHandler) HandleInput(msg tea.Msg, context *StateContext) (StateHandler, tea.Cmd) {
+	if cmd, handled := handlePause(msg, &h.stopwatch); handled {
+		return h, cmd
+	}
+
 	var commands []tea.Cmd
 	switch msg := msg.(type) {
 	case stopwatch.StartStopMsg:
@@ -86,6 +90,7 @@ func (h *ZenModeHandler) Render(m *model) string {
 	termWidth, termHeight := m.width-2, m.height-2
 
 	stopwatch := style(h.stopwatch.stopwatch.View(), m.styles.themeFunc)
+	stopwatch += pausedLabel(&h.stopwatch, m.styles)
 	paragraphView := h.base.renderParagraphZenMode(lineLenLimit, m.styles)
 	lines := strings.Split(paragraphView, "\n")
 
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// BigramStat summarises how a user typed one pair of adjacent characters.
+// TotalMs is the summed time between the two key presses.
+type BigramStat struct {
+	Bigram      string
+	Occurrences int
+	Mistakes    int
+	TotalMs     int64
+}
+
+func SaveBigramStats(db *sql.DB, userID int64, testID int64, stats []BigramStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO bigram_stats
+		(user_id, test_id, bigram, occurrences, mistakes, total_ms)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, stat := range stats {
+		_, err := stmt.Exec(userID, testID, stat.Bigram, stat.Occurrences, stat.Mistakes, stat.TotalMs)
+		if err != nil {
+			return fmt.Errorf("failed to save bigram stats: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBigramStats totals a user's bigram stats over their most recent tests.
+func GetBigramStats(db *sql.DB, userID int64, recentTests int) ([]BigramStat, error) {
+	rows, err := db.Query(
+		`SELECT bigram, SUM(occurrences), SUM(mistakes), SUM(total_ms)
+		 FROM bigram_stats
+		 WHERE user_id = ? AND test_id IN (
+			SELECT id FROM test_history
+			WHERE user_id = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		 )
+		 GROUP BY bigram`,
+		userID, userID, recentTests,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []BigramStat
+	for rows.Next() {
+		var s BigramStat
+		if err := rows.Scan(&s.Bigram, &s.Occurrences, &s.Mistakes, &s.TotalMs); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
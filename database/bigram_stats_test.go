@@ -0,0 +1,59 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestBigramStatsRecentTests(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		record := &TestRecord{UserID: 1, TestType: "practice", TestValue: 30, Duration: 20}
+		if err := SaveTestResult(db, record); err != nil {
+			t.Fatalf("SaveTestResult failed: %v", err)
+		}
+		if record.ID == 0 {
+			t.Fatal("SaveTestResult should set the record ID")
+		}
+
+		stats := []BigramStat{
+			{Bigram: "th", Occurrences: 10, Mistakes: i, TotalMs: 1000},
+			{Bigram: "qu", Occurrences: 2, Mistakes: 1, TotalMs: 600},
+		}
+		if err := SaveBigramStats(db, 1, record.ID, stats); err != nil {
+			t.Fatalf("SaveBigramStats failed: %v", err)
+		}
+	}
+
+	stats, err := GetBigramStats(db, 1, 2)
+	if err != nil {
+		t.Fatalf("GetBigramStats failed: %v", err)
+	}
+
+	byBigram := make(map[string]BigramStat)
+	for _, s := range stats {
+		byBigram[s.Bigram] = s
+	}
+
+	th := byBigram["th"]
+	if th.Occurrences != 20 || th.Mistakes != 3 || th.TotalMs != 2000 {
+		t.Errorf("expected th to total the two most recent tests, got %+v", th)
+	}
+	if byBigram["qu"].Occurrences != 4 {
+		t.Errorf("expected qu to total the two most recent tests, got %+v", byBigram["qu"])
+	}
+
+	others, err := GetBigramStats(db, 2, 10)
+	if err != nil {
+		t.Fatalf("GetBigramStats failed: %v", err)
+	}
+	if len(others) != 0 {
+		t.Errorf("expected no stats for another user, got %v", others)
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// GetBookProgress returns the passage a user is up to in a book, or 0 when
+// they haven't started it.
+func GetBookProgress(db *sql.DB, userID int64, book string) (int, error) {
+	var passage int
+	err := db.QueryRow(
+		"SELECT passage FROM book_progress WHERE user_id = ? AND book = ?",
+		userID, book,
+	).Scan(&passage)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return passage, nil
+}
+
+func SaveBookProgress(db *sql.DB, userID int64, book string, passage int) error {
+	_, err := db.Exec(
+		`INSERT INTO book_progress (user_id, book, passage, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(user_id, book) DO UPDATE SET
+			passage = excluded.passage,
+			updated_at = excluded.updated_at`,
+		userID, book, passage,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save book progress: %w", err)
+	}
+
+	return nil
+}
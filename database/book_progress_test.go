@@ -0,0 +1,40 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestBookProgress(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	passage, err := GetBookProgress(db, 1, "moby-dick.txt")
+	if err != nil || passage != 0 {
+		t.Fatalf("expected a new book to start at 0, got %d (%v)", passage, err)
+	}
+
+	if err := SaveBookProgress(db, 1, "moby-dick.txt", 3); err != nil {
+		t.Fatalf("SaveBookProgress failed: %v", err)
+	}
+	if err := SaveBookProgress(db, 1, "moby-dick.txt", 4); err != nil {
+		t.Fatalf("SaveBookProgress failed: %v", err)
+	}
+	if err := SaveBookProgress(db, 1, "walden.md", 9); err != nil {
+		t.Fatalf("SaveBookProgress failed: %v", err)
+	}
+
+	if passage, _ := GetBookProgress(db, 1, "moby-dick.txt"); passage != 4 {
+		t.Errorf("expected the latest passage 4, got %d", passage)
+	}
+	if passage, _ := GetBookProgress(db, 1, "walden.md"); passage != 9 {
+		t.Errorf("expected books to be tracked separately, got %d", passage)
+	}
+	if passage, _ := GetBookProgress(db, 2, "walden.md"); passage != 0 {
+		t.Errorf("expected users to be tracked separately, got %d", passage)
+	}
+}
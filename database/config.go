@@ -16,10 +16,48 @@ var (
 )
 
 type UserConfig struct {
-	Time        int    `json:"time" default:"30" validate:"min=1,max=1440"`
-	Words       int    `json:"words" default:"30" validate:"min=1,max=500"`
-	Punctuation bool   `json:"punctuation" default:"false"`
-	Theme       string `json:"theme" default:"magenta"`
+	Time         int    `json:"time" default:"30" validate:"min=1,max=1440"`
+	Words        int    `json:"words" default:"30" validate:"min=1,max=500"`
+	Punctuation  bool   `json:"punctuation" default:"false"`
+	Theme        string `json:"theme" default:"magenta"`
+	QuoteLength  string `json:"quote_length" default:"all" validate:"omitempty,oneof=all short medium long"`
+	WordList     string `json:"word_list" default:"Common words" validate:"max=64"`
+	Language     string `json:"language" default:"English" validate:"max=32"`
+	CodeLanguage string `json:"code_language" default:"" validate:"max=32"`
+
+	// Frequency still accepts top1k and top10k, tiers that were dropped, so
+	// configs saved with them stay valid. They are read as all.
+	Frequency string `json:"frequency" default:"all" validate:"omitempty,oneof=all top200 rare top1k top10k"`
+
+	PunctuationProfile string             `json:"punctuation_profile" default:"normal" validate:"omitempty,oneof=light normal heavy custom"`
+	PunctuationWeights map[string]float64 `json:"punctuation_weights" validate:"omitempty,dive,keys,oneof=comma period question exclamation semicolon colon dash parenthesis quote nested_quote contraction hyphenated,endkeys,min=0,max=1"`
+
+	Numbers bool `json:"numbers" default:"false"`
+	Symbols bool `json:"symbols" default:"false"`
+
+	KeyFilter     string `json:"key_filter" default:"none" validate:"omitempty,oneof=none home left right top custom"`
+	KeyFilterKeys string `json:"key_filter_keys" default:"" validate:"max=64"`
+
+	// WordInput makes space jump to the next word and lets letters be typed
+	// past the end of a word, instead of checking every key against the next
+	// character of the text.
+	WordInput bool `json:"word_input" default:"false"`
+
+	// FailMode ends a test as failed on the first wrong key, or on leaving a
+	// word with an uncorrected error. MinWpm and MinAccuracy fail it when the
+	// speed or accuracy so far drops under them; 0 turns them off.
+	FailMode    string `json:"fail_mode" default:"off" validate:"omitempty,oneof=off sudden_death perfectionist"`
+	MinWpm      int    `json:"min_wpm" default:"0" validate:"min=0,max=300"`
+	MinAccuracy int    `json:"min_accuracy" default:"0" validate:"min=0,max=100"`
+
+	// PaceCaret runs a second caret through the text at PaceWpm, or at the
+	// user's average or best for the test being taken.
+	PaceCaret string `json:"pace_caret" default:"off" validate:"omitempty,oneof=off target average best"`
+	PaceWpm   int    `json:"pace_wpm" default:"0" validate:"min=0,max=300"`
+
+	// LiveStats shows net and raw WPM, accuracy and errors under the timer
+	// while a timer or word count test runs.
+	LiveStats bool `json:"live_stats" default:"false"`
 
 	CustomSettings map[string]interface{} `json:"custom_settings"`
 }
@@ -109,3 +147,19 @@ func validateConfig(cfg UserConfig) error {
 	validate := validator.New()
 	return validate.Struct(cfg)
 }
+
+// ValidateConfigField checks a single field of cfg, e.g. "Time", against its
+// validate tag so a form can reject a value before it is saved.
+func ValidateConfigField(cfg UserConfig, field string) error {
+	validate := validator.New()
+	err := validate.StructPartial(cfg, field)
+	if errs, ok := err.(validator.ValidationErrors); ok && len(errs) > 0 {
+		switch errs[0].Tag() {
+		case "min":
+			return fmt.Errorf("must be at least %s", errs[0].Param())
+		case "max":
+			return fmt.Errorf("must be at most %s", errs[0].Param())
+		}
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package database
+
+import "testing"
+
+func TestValidateConfigField(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     UserConfig
+		field   string
+		wantErr string
+	}{
+		{name: "five minute timer", cfg: UserConfig{Time: 300}, field: "Time"},
+		{name: "timer too long", cfg: UserConfig{Time: 1441}, field: "Time", wantErr: "must be at most 1440"},
+		{name: "no words", cfg: UserConfig{Words: 0}, field: "Words", wantErr: "must be at least 1"},
+		{name: "other fields ignored", cfg: UserConfig{Words: 200, Time: 0}, field: "Words"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfigField(tt.cfg, tt.field)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
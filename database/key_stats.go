@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// KeyStat summarises how a user typed one key. Presses counts every attempt
+// at the key and Mistakes the wrong ones; TotalMs sums the time before the
+// TimedPresses that followed another forward key press.
+type KeyStat struct {
+	Key          string
+	Presses      int
+	Mistakes     int
+	TimedPresses int
+	TotalMs      int64
+}
+
+func SaveKeyStats(db *sql.DB, userID int64, testID int64, stats []KeyStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO key_stats
+		(user_id, test_id, key, presses, mistakes, timed_presses, total_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, stat := range stats {
+		_, err := stmt.Exec(userID, testID, stat.Key, stat.Presses, stat.Mistakes, stat.TimedPresses, stat.TotalMs)
+		if err != nil {
+			return fmt.Errorf("failed to save key stats: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetKeyStats totals a user's key stats over their most recent tests.
+func GetKeyStats(db *sql.DB, userID int64, recentTests int) ([]KeyStat, error) {
+	rows, err := db.Query(
+		`SELECT key, SUM(presses), SUM(mistakes), SUM(timed_presses), SUM(total_ms)
+		 FROM key_stats
+		 WHERE user_id = ? AND test_id IN (
+			SELECT id FROM test_history
+			WHERE user_id = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		 )
+		 GROUP BY key`,
+		userID, userID, recentTests,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []KeyStat
+	for rows.Next() {
+		var s KeyStat
+		if err := rows.Scan(&s.Key, &s.Presses, &s.Mistakes, &s.TimedPresses, &s.TotalMs); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
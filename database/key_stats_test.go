@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestKeyStatsRecentTests(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		record := &TestRecord{UserID: 1, TestType: "timer", TestValue: 30, Duration: 30}
+		if err := SaveTestResult(db, record); err != nil {
+			t.Fatalf("SaveTestResult failed: %v", err)
+		}
+
+		stats := []KeyStat{
+			{Key: "e", Presses: 20, Mistakes: i, TimedPresses: 18, TotalMs: 1800},
+			{Key: " ", Presses: 10, Mistakes: 0, TimedPresses: 10, TotalMs: 1500},
+		}
+		if err := SaveKeyStats(db, 1, record.ID, stats); err != nil {
+			t.Fatalf("SaveKeyStats failed: %v", err)
+		}
+	}
+
+	stats, err := GetKeyStats(db, 1, 2)
+	if err != nil {
+		t.Fatalf("GetKeyStats failed: %v", err)
+	}
+
+	byKey := make(map[string]KeyStat)
+	for _, s := range stats {
+		byKey[s.Key] = s
+	}
+
+	e := byKey["e"]
+	if e.Presses != 40 || e.Mistakes != 3 || e.TimedPresses != 36 || e.TotalMs != 3600 {
+		t.Errorf("expected e to total the two most recent tests, got %+v", e)
+	}
+	if byKey[" "].Presses != 20 {
+		t.Errorf("expected space to total the two most recent tests, got %+v", byKey[" "])
+	}
+
+	others, err := GetKeyStats(db, 2, 10)
+	if err != nil {
+		t.Fatalf("GetKeyStats failed: %v", err)
+	}
+	if len(others) != 0 {
+		t.Errorf("expected no stats for another user, got %v", others)
+	}
+}
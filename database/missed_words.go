@@ -0,0 +1,76 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MissedWord is a word the user mistyped and how many times they did.
+type MissedWord struct {
+	Word   string
+	Misses int
+}
+
+func SaveMissedWords(db *sql.DB, userID int64, testID int64, missed []MissedWord) error {
+	if len(missed) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO missed_words
+		(user_id, test_id, word, misses)
+		VALUES (?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, word := range missed {
+		_, err := stmt.Exec(userID, testID, word.Word, word.Misses)
+		if err != nil {
+			return fmt.Errorf("failed to save missed words: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMissedWords totals the words a user missed over their most recent tests,
+// most missed first.
+func GetMissedWords(db *sql.DB, userID int64, recentTests int) ([]MissedWord, error) {
+	rows, err := db.Query(
+		`SELECT word, SUM(misses) AS total
+		 FROM missed_words
+		 WHERE user_id = ? AND test_id IN (
+			SELECT id FROM test_history
+			WHERE user_id = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		 )
+		 GROUP BY word
+		 ORDER BY total DESC, word`,
+		userID, userID, recentTests,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missed []MissedWord
+	for rows.Next() {
+		var w MissedWord
+		if err := rows.Scan(&w.Word, &w.Misses); err != nil {
+			return nil, err
+		}
+		missed = append(missed, w)
+	}
+
+	return missed, rows.Err()
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestMissedWordsRecentTests(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	tests := [][]MissedWord{
+		{{Word: "ancient", Misses: 5}},
+		{{Word: "rhythm", Misses: 1}, {Word: "their", Misses: 1}},
+		{{Word: "rhythm", Misses: 2}, {Word: "mistakes", Misses: 1}},
+	}
+	for i, missed := range tests {
+		testType := "words"
+		if i == 2 {
+			testType = "mistakes"
+		}
+		record := &TestRecord{UserID: 1, TestType: testType, TestValue: 25, Duration: 20}
+		if err := SaveTestResult(db, record); err != nil {
+			t.Fatalf("SaveTestResult failed: %v", err)
+		}
+		if err := SaveMissedWords(db, 1, record.ID, missed); err != nil {
+			t.Fatalf("SaveMissedWords failed: %v", err)
+		}
+	}
+
+	missed, err := GetMissedWords(db, 1, 2)
+	if err != nil {
+		t.Fatalf("GetMissedWords failed: %v", err)
+	}
+
+	want := []MissedWord{{"rhythm", 3}, {"mistakes", 1}, {"their", 1}}
+	if len(missed) != len(want) {
+		t.Fatalf("expected %v from the two most recent tests, got %v", want, missed)
+	}
+	for i := range want {
+		if missed[i] != want[i] {
+			t.Errorf("expected %v at %d, got %v", want[i], i, missed[i])
+		}
+	}
+
+	others, err := GetMissedWords(db, 2, 10)
+	if err != nil {
+		t.Fatalf("GetMissedWords failed: %v", err)
+	}
+	if len(others) != 0 {
+		t.Errorf("expected no missed words for another user, got %v", others)
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// ReplayKey is one key press of a stored attempt, Ms after the test started.
+// Backspace is stored as '\b'.
+type ReplayKey struct {
+	Key rune  `json:"k"`
+	Ms  int64 `json:"t"`
+}
+
+// Replay is a stored attempt at the text of a seed code. WordInput is the
+// input mode it was typed in, which the keys have to be played back with.
+type Replay struct {
+	Seed      string
+	WordInput bool
+	Keys      []ReplayKey
+}
+
+// SaveReplay keeps the key presses of a seeded test so it can be raced as a
+// ghost later.
+func SaveReplay(db *sql.DB, userID int64, testID int64, replay Replay) error {
+	if len(replay.Keys) == 0 || replay.Seed == "" {
+		return nil
+	}
+
+	encoded, err := json.Marshal(replay.Keys)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay: %w", err)
+	}
+
+	_, err = db.Exec(
+		`INSERT INTO replays (user_id, test_id, seed, word_input, keys) VALUES (?, ?, ?, ?, ?)`,
+		userID, testID, replay.Seed, replay.WordInput, string(encoded),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save replay: %w", err)
+	}
+	return nil
+}
+
+// GetLatestReplay returns the user's latest stored attempt at the text of
+// seed, or nil when there is none.
+func GetLatestReplay(db *sql.DB, userID int64, seed string) (*Replay, error) {
+	replay := Replay{Seed: seed}
+	var encoded string
+	err := db.QueryRow(
+		`SELECT word_input, keys FROM replays
+		 WHERE user_id = ? AND seed = ?
+		 ORDER BY id DESC
+		 LIMIT 1`,
+		userID, seed,
+	).Scan(&replay.WordInput, &encoded)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(encoded), &replay.Keys); err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %w", err)
+	}
+	return &replay, nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestLatestReplay(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	attempts := []Replay{
+		{Seed: "w10-abc", Keys: []ReplayKey{{Key: 't', Ms: 0}, {Key: 'x', Ms: 150}}},
+		{Seed: "w10-abc", WordInput: true, Keys: []ReplayKey{{Key: 't', Ms: 0}, {Key: 'x', Ms: 120}, {Key: '\b', Ms: 300}, {Key: 'h', Ms: 410}}},
+	}
+	for _, attempt := range attempts {
+		record := &TestRecord{UserID: 1, TestType: "words", TestValue: 10, Seed: attempt.Seed}
+		if err := SaveTestResult(db, record); err != nil {
+			t.Fatalf("SaveTestResult failed: %v", err)
+		}
+		if err := SaveReplay(db, 1, record.ID, attempt); err != nil {
+			t.Fatalf("SaveReplay failed: %v", err)
+		}
+	}
+
+	replay, err := GetLatestReplay(db, 1, "w10-abc")
+	if err != nil {
+		t.Fatalf("GetLatestReplay failed: %v", err)
+	}
+	if replay == nil || len(replay.Keys) != 4 || replay.Keys[2] != (ReplayKey{Key: '\b', Ms: 300}) {
+		t.Fatalf("expected the latest attempt, got %+v", replay)
+	}
+	if !replay.WordInput {
+		t.Error("expected the latest attempt's word input mode to be kept")
+	}
+
+	for _, seed := range []string{"w10-other", ""} {
+		replay, err := GetLatestReplay(db, 1, seed)
+		if err != nil || replay != nil {
+			t.Errorf("expected no replay for seed %q, got %+v (err %v)", seed, replay, err)
+		}
+	}
+}
@@ -18,7 +18,30 @@ type TestRecord struct {
 	IsPunctuation bool
 	RawChars      int
 	MistakesCount int
-	CreatedAt     time.Time
+	// Seed is the seed code the test text was generated from, or empty for
+	// tests whose text isn't generated.
+	Seed string
+	// Numbers and Symbols record whether digit groups and programming
+	// symbols were mixed into the text.
+	Numbers bool
+	Symbols bool
+	// KeyFilter is the key set the words were limited to, e.g. "home" or
+	// "custom:asdf", or empty when every word was allowed.
+	KeyFilter string
+	// CorrectChars, IncorrectChars, ExtraChars and MissedChars account for
+	// every character of the typed words: typed right, typed wrong, typed
+	// past the end of a word, and skipped.
+	CorrectChars   int
+	IncorrectChars int
+	ExtraChars     int
+	MissedChars    int
+	// Flagged says why a test looks like it wasn't typed by hand, or is
+	// empty for tests that passed the checks.
+	Flagged string
+	// Failed is set for tests ended early by a failure mode or a minimum
+	// speed or accuracy.
+	Failed    bool
+	CreatedAt time.Time
 }
 
 const maxTestHistory = 1000
@@ -35,18 +58,27 @@ func SaveTestResult(db *sql.DB, record *TestRecord) error {
 		isPunct = 1
 	}
 
-	_, err = tx.Exec(
+	result, err := tx.Exec(
 		`INSERT INTO test_history
-		(user_id, test_type, test_value, duration_seconds, wpm, words_typed, accuracy, isPunctuation, raw_chars, mistakes_count)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		(user_id, test_type, test_value, duration_seconds, wpm, words_typed, accuracy, isPunctuation, raw_chars, mistakes_count, seed, numbers, symbols, key_filter,
+		correct_chars, incorrect_chars, extra_chars, missed_chars, flagged, failed)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		record.UserID, record.TestType, record.TestValue, record.Duration,
 		record.WPM, record.WordsTyped, record.Accuracy, isPunct,
-		record.RawChars, record.MistakesCount,
+		record.RawChars, record.MistakesCount, record.Seed,
+		record.Numbers, record.Symbols, record.KeyFilter,
+		record.CorrectChars, record.IncorrectChars, record.ExtraChars, record.MissedChars,
+		record.Flagged, record.Failed,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save test result: %w", err)
 	}
 
+	record.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to save test result: %w", err)
+	}
+
 	_, err = tx.Exec(
 		`DELETE FROM test_history
 		WHERE user_id = ? AND id NOT IN (
@@ -61,18 +93,76 @@ func SaveTestResult(db *sql.DB, record *TestRecord) error {
 		return fmt.Errorf("failed to prune test history: %w", err)
 	}
 
+	_, err = tx.Exec(
+		`DELETE FROM bigram_stats
+		WHERE user_id = ? AND test_id NOT IN (
+			SELECT id FROM test_history WHERE user_id = ?
+		)`,
+		record.UserID, record.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune bigram stats: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM missed_words
+		WHERE user_id = ? AND test_id NOT IN (
+			SELECT id FROM test_history WHERE user_id = ?
+		)`,
+		record.UserID, record.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune missed words: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM key_stats
+		WHERE user_id = ? AND test_id NOT IN (
+			SELECT id FROM test_history WHERE user_id = ?
+		)`,
+		record.UserID, record.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune key stats: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM replays
+		WHERE user_id = ? AND test_id NOT IN (
+			SELECT id FROM test_history WHERE user_id = ?
+		)`,
+		record.UserID, record.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune replays: %w", err)
+	}
+
 	return tx.Commit()
 }
 
+// HistoryFilter narrows test history down to one kind of test, e.g. timer
+// tests of 300 seconds. An empty TestType or a zero TestValue matches any.
+type HistoryFilter struct {
+	TestType  string
+	TestValue int
+}
+
 func GetTestHistory(db *sql.DB, userID int64, limit int) ([]TestRecord, error) {
+	return GetFilteredTestHistory(db, userID, HistoryFilter{}, limit)
+}
+
+func GetFilteredTestHistory(db *sql.DB, userID int64, filter HistoryFilter, limit int) ([]TestRecord, error) {
 	rows, err := db.Query(
 		`SELECT id, user_id, test_type, test_value, duration_seconds, wpm, words_typed,
-		 accuracy, isPunctuation, raw_chars, mistakes_count, created_at
+		 accuracy, isPunctuation, raw_chars, mistakes_count, seed, numbers, symbols, key_filter,
+		 correct_chars, incorrect_chars, extra_chars, missed_chars, flagged, failed, created_at
 		 FROM test_history
 		 WHERE user_id = ?
+		   AND (? = '' OR test_type = ?)
+		   AND (? = 0 OR test_value = ?)
 		 ORDER BY created_at DESC
 		 LIMIT ?`,
-		userID, limit,
+		userID, filter.TestType, filter.TestType, filter.TestValue, filter.TestValue, limit,
 	)
 	if err != nil {
 		return nil, err
@@ -86,7 +176,8 @@ func GetTestHistory(db *sql.DB, userID int64, limit int) ([]TestRecord, error) {
 		err := rows.Scan(
 			&r.ID, &r.UserID, &r.TestType, &r.TestValue, &r.Duration,
 			&r.WPM, &r.WordsTyped, &r.Accuracy, &isPunct,
-			&r.RawChars, &r.MistakesCount, &r.CreatedAt,
+			&r.RawChars, &r.MistakesCount, &r.Seed, &r.Numbers, &r.Symbols, &r.KeyFilter,
+			&r.CorrectChars, &r.IncorrectChars, &r.ExtraChars, &r.MissedChars, &r.Flagged, &r.Failed, &r.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -98,8 +189,49 @@ func GetTestHistory(db *sql.DB, userID int64, limit int) ([]TestRecord, error) {
 	return records, rows.Err()
 }
 
+// GetHistoryFilters lists every timer and word-count length a user has
+// history for, custom lengths included, so each can be picked as a filter.
+func GetHistoryFilters(db *sql.DB, userID int64) ([]HistoryFilter, error) {
+	rows, err := db.Query(
+		`SELECT DISTINCT test_type, test_value
+		 FROM test_history
+		 WHERE user_id = ? AND test_type IN ('timer', 'words')
+		 ORDER BY test_type, test_value`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filters []HistoryFilter
+	for rows.Next() {
+		var f HistoryFilter
+		if err := rows.Scan(&f.TestType, &f.TestValue); err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, rows.Err()
+}
+
 func GetTestCount(db *sql.DB, userID int64) (int, error) {
 	var count int
 	err := db.QueryRow("SELECT COUNT(*) FROM test_history WHERE user_id = ?", userID).Scan(&count)
 	return count, err
 }
+
+// GetPaceStats returns a user's average and best WPM over the tests matching
+// filter, leaving out failed and flagged ones. Both are 0 without any tests.
+func GetPaceStats(db *sql.DB, userID int64, filter HistoryFilter) (average, best float64, err error) {
+	err = db.QueryRow(
+		`SELECT COALESCE(AVG(wpm), 0), COALESCE(MAX(wpm), 0)
+		 FROM test_history
+		 WHERE user_id = ? AND failed = 0 AND flagged = ''
+		   AND (? = '' OR test_type = ?)
+		   AND (? = 0 OR test_value = ?)`,
+		userID, filter.TestType, filter.TestType, filter.TestValue, filter.TestValue,
+	).Scan(&average, &best)
+	return average, best, err
+}
@@ -30,7 +30,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 	_, err = db.Exec(`CREATE TABLE test_history (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		user_id INTEGER NOT NULL,
-		test_type TEXT NOT NULL CHECK(test_type IN ('timer', 'words', 'zen')),
+		test_type TEXT NOT NULL CHECK(test_type IN ('timer', 'words', 'zen', 'quote', 'code', 'practice', 'book', 'mistakes')),
 		test_value INTEGER NOT NULL,
 		duration_seconds REAL NOT NULL,
 		wpm REAL NOT NULL,
@@ -40,12 +40,92 @@ func setupTestDB(t *testing.T) *sql.DB {
 		raw_chars INTEGER NOT NULL,
 		mistakes_count INTEGER NOT NULL,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		seed TEXT NOT NULL DEFAULT '',
+		numbers BOOLEAN NOT NULL DEFAULT 0,
+		symbols BOOLEAN NOT NULL DEFAULT 0,
+		key_filter TEXT NOT NULL DEFAULT '',
+		correct_chars INTEGER NOT NULL DEFAULT 0,
+		incorrect_chars INTEGER NOT NULL DEFAULT 0,
+		extra_chars INTEGER NOT NULL DEFAULT 0,
+		missed_chars INTEGER NOT NULL DEFAULT 0,
+		flagged TEXT NOT NULL DEFAULT '',
+		failed BOOLEAN NOT NULL DEFAULT 0,
 		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
 	)`)
 	if err != nil {
 		t.Fatalf("failed to create test_history table: %v", err)
 	}
 
+	_, err = db.Exec(`CREATE TABLE bigram_stats (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		test_id INTEGER NOT NULL,
+		bigram TEXT NOT NULL,
+		occurrences INTEGER NOT NULL,
+		mistakes INTEGER NOT NULL,
+		total_ms INTEGER NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create bigram_stats table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE missed_words (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		test_id INTEGER NOT NULL,
+		word TEXT NOT NULL,
+		misses INTEGER NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create missed_words table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE key_stats (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		test_id INTEGER NOT NULL,
+		key TEXT NOT NULL,
+		presses INTEGER NOT NULL,
+		mistakes INTEGER NOT NULL,
+		timed_presses INTEGER NOT NULL,
+		total_ms INTEGER NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create key_stats table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE replays (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		test_id INTEGER NOT NULL,
+		seed TEXT NOT NULL,
+		word_input BOOLEAN NOT NULL DEFAULT 0,
+		keys TEXT NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create replays table: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE book_progress (
+		user_id INTEGER NOT NULL,
+		book TEXT NOT NULL,
+		passage INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(user_id, book),
+		FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create book_progress table: %v", err)
+	}
+
 	return db
 }
 
@@ -351,3 +431,197 @@ func TestTestHistoryOrdering(t *testing.T) {
 		}
 	}
 }
+
+func TestSeedRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	seeded := &TestRecord{UserID: 1, TestType: "timer", TestValue: 30, Duration: 30, Seed: "t30p-en-1A2B3C"}
+	if err := SaveTestResult(db, seeded); err != nil {
+		t.Fatalf("SaveTestResult failed: %v", err)
+	}
+	quote := &TestRecord{UserID: 1, TestType: "quote", TestValue: 4, Duration: 12}
+	if err := SaveTestResult(db, quote); err != nil {
+		t.Fatalf("SaveTestResult failed: %v", err)
+	}
+
+	records, err := GetTestHistory(db, 1, 10)
+	if err != nil {
+		t.Fatalf("GetTestHistory failed: %v", err)
+	}
+
+	seeds := make(map[string]string)
+	for _, r := range records {
+		seeds[r.TestType] = r.Seed
+	}
+	if seeds["timer"] != "t30p-en-1A2B3C" {
+		t.Errorf("expected the seed code to round-trip, got %q", seeds["timer"])
+	}
+	if seeds["quote"] != "" {
+		t.Errorf("expected no seed for a quote test, got %q", seeds["quote"])
+	}
+}
+
+func TestDrillOptionsRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	drill := &TestRecord{UserID: 1, TestType: "words", TestValue: 30, Duration: 20, Numbers: true, Symbols: true, KeyFilter: "custom:asdf"}
+	if err := SaveTestResult(db, drill); err != nil {
+		t.Fatalf("SaveTestResult failed: %v", err)
+	}
+	plain := &TestRecord{UserID: 1, TestType: "timer", TestValue: 30, Duration: 30}
+	if err := SaveTestResult(db, plain); err != nil {
+		t.Fatalf("SaveTestResult failed: %v", err)
+	}
+
+	records, err := GetTestHistory(db, 1, 10)
+	if err != nil {
+		t.Fatalf("GetTestHistory failed: %v", err)
+	}
+	for _, r := range records {
+		want := r.TestType == "words"
+		if r.Numbers != want || r.Symbols != want {
+			t.Errorf("%s test: expected numbers and symbols %t, got %t and %t", r.TestType, want, r.Numbers, r.Symbols)
+		}
+		if want && r.KeyFilter != "custom:asdf" || !want && r.KeyFilter != "" {
+			t.Errorf("%s test: unexpected key filter %q", r.TestType, r.KeyFilter)
+		}
+	}
+}
+
+func TestCharacterCountsAndFlagRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	record := &TestRecord{
+		UserID: 1, TestType: "timer", TestValue: 30, Duration: 30,
+		CorrectChars: 240, IncorrectChars: 3, ExtraChars: 2, MissedChars: 1,
+		Flagged: "tried to paste text", Failed: true,
+	}
+	if err := SaveTestResult(db, record); err != nil {
+		t.Fatalf("SaveTestResult failed: %v", err)
+	}
+
+	records, err := GetTestHistory(db, 1, 1)
+	if err != nil {
+		t.Fatalf("GetTestHistory failed: %v", err)
+	}
+	r := records[0]
+	if r.CorrectChars != 240 || r.IncorrectChars != 3 || r.ExtraChars != 2 || r.MissedChars != 1 {
+		t.Errorf("expected 240/3/2/1 characters, got %d/%d/%d/%d", r.CorrectChars, r.IncorrectChars, r.ExtraChars, r.MissedChars)
+	}
+	if r.Flagged != record.Flagged {
+		t.Errorf("expected flag %q, got %q", record.Flagged, r.Flagged)
+	}
+	if !r.Failed {
+		t.Error("expected the test to be stored as failed")
+	}
+}
+
+func TestHistoryFiltersIncludeCustomValues(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	tests := []struct {
+		testType  string
+		testValue int
+	}{
+		{"timer", 30}, {"timer", 300}, {"timer", 300}, {"words", 200}, {"quote", 12},
+	}
+	for _, tt := range tests {
+		record := &TestRecord{UserID: 1, TestType: tt.testType, TestValue: tt.testValue, Duration: 30}
+		if err := SaveTestResult(db, record); err != nil {
+			t.Fatalf("SaveTestResult failed: %v", err)
+		}
+	}
+
+	filters, err := GetHistoryFilters(db, 1)
+	if err != nil {
+		t.Fatalf("GetHistoryFilters failed: %v", err)
+	}
+	want := []HistoryFilter{{"timer", 30}, {"timer", 300}, {"words", 200}}
+	if len(filters) != len(want) {
+		t.Fatalf("expected filters %v, got %v", want, filters)
+	}
+	for i := range want {
+		if filters[i] != want[i] {
+			t.Errorf("expected %v at %d, got %v", want[i], i, filters[i])
+		}
+	}
+
+	records, err := GetFilteredTestHistory(db, 1, HistoryFilter{TestType: "timer", TestValue: 300}, 10)
+	if err != nil {
+		t.Fatalf("GetFilteredTestHistory failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("expected 2 five-minute tests, got %d", len(records))
+	}
+
+	records, err = GetFilteredTestHistory(db, 1, HistoryFilter{TestType: "timer"}, 10)
+	if err != nil {
+		t.Fatalf("GetFilteredTestHistory failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected 3 timer tests, got %d", len(records))
+	}
+}
+
+func TestGetPaceStats(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO users (email, password, salt) VALUES ('test@test.com', 'hash', 'salt')")
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	records := []*TestRecord{
+		{UserID: 1, TestType: "timer", TestValue: 30, WPM: 60},
+		{UserID: 1, TestType: "timer", TestValue: 30, WPM: 80},
+		{UserID: 1, TestType: "timer", TestValue: 30, WPM: 150, Flagged: "tried to paste text"},
+		{UserID: 1, TestType: "timer", TestValue: 30, WPM: 140, Failed: true},
+		{UserID: 1, TestType: "timer", TestValue: 60, WPM: 100},
+	}
+	for _, record := range records {
+		if err := SaveTestResult(db, record); err != nil {
+			t.Fatalf("SaveTestResult failed: %v", err)
+		}
+	}
+
+	average, best, err := GetPaceStats(db, 1, HistoryFilter{TestType: "timer", TestValue: 30})
+	if err != nil {
+		t.Fatalf("GetPaceStats failed: %v", err)
+	}
+	if average != 70 || best != 80 {
+		t.Errorf("expected average 70 and best 80, got %v and %v", average, best)
+	}
+
+	average, best, err = GetPaceStats(db, 1, HistoryFilter{TestType: "words", TestValue: 30})
+	if err != nil {
+		t.Fatalf("GetPaceStats failed: %v", err)
+	}
+	if average != 0 || best != 0 {
+		t.Errorf("expected no pace without tests, got %v and %v", average, best)
+	}
+}
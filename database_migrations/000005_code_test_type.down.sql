@@ -0,0 +1,28 @@
+PRAGMA foreign_keys = OFF;
+
+DELETE FROM test_history WHERE test_type = 'code';
+
+CREATE TABLE test_history_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    test_type TEXT NOT NULL CHECK(test_type IN ('timer', 'words', 'zen', 'quote')),
+    test_value INTEGER NOT NULL,
+    duration_seconds REAL NOT NULL,
+    wpm REAL NOT NULL,
+    words_typed INTEGER NOT NULL,
+    accuracy REAL NOT NULL,
+    isPunctuation BOOLEAN NOT NULL DEFAULT 0,
+    raw_chars INTEGER NOT NULL,
+    mistakes_count INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO test_history_old SELECT * FROM test_history;
+DROP TABLE test_history;
+ALTER TABLE test_history_old RENAME TO test_history;
+
+CREATE INDEX idx_test_history_user_id ON test_history(user_id);
+CREATE INDEX idx_test_history_created_at ON test_history(created_at);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1 @@
+ALTER TABLE test_history DROP COLUMN seed;
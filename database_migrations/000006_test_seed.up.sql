@@ -0,0 +1 @@
+ALTER TABLE test_history ADD COLUMN seed TEXT NOT NULL DEFAULT '';
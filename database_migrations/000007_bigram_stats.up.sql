@@ -0,0 +1,42 @@
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE test_history_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    test_type TEXT NOT NULL CHECK(test_type IN ('timer', 'words', 'zen', 'quote', 'code', 'practice')),
+    test_value INTEGER NOT NULL,
+    duration_seconds REAL NOT NULL,
+    wpm REAL NOT NULL,
+    words_typed INTEGER NOT NULL,
+    accuracy REAL NOT NULL,
+    isPunctuation BOOLEAN NOT NULL DEFAULT 0,
+    raw_chars INTEGER NOT NULL,
+    mistakes_count INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    seed TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO test_history_new SELECT * FROM test_history;
+DROP TABLE test_history;
+ALTER TABLE test_history_new RENAME TO test_history;
+
+CREATE INDEX idx_test_history_user_id ON test_history(user_id);
+CREATE INDEX idx_test_history_created_at ON test_history(created_at);
+
+CREATE TABLE bigram_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    test_id INTEGER NOT NULL,
+    bigram TEXT NOT NULL,
+    occurrences INTEGER NOT NULL,
+    mistakes INTEGER NOT NULL,
+    total_ms INTEGER NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bigram_stats_user_id ON bigram_stats(user_id);
+CREATE INDEX idx_bigram_stats_test_id ON bigram_stats(test_id);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,2 @@
+ALTER TABLE test_history DROP COLUMN symbols;
+ALTER TABLE test_history DROP COLUMN numbers;
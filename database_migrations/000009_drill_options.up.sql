@@ -0,0 +1,2 @@
+ALTER TABLE test_history ADD COLUMN numbers BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE test_history ADD COLUMN symbols BOOLEAN NOT NULL DEFAULT 0;
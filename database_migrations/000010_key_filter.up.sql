@@ -0,0 +1 @@
+ALTER TABLE test_history ADD COLUMN key_filter TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+CREATE TABLE key_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    test_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    presses INTEGER NOT NULL,
+    mistakes INTEGER NOT NULL,
+    timed_presses INTEGER NOT NULL,
+    total_ms INTEGER NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_key_stats_user_id ON key_stats(user_id);
+CREATE INDEX idx_key_stats_test_id ON key_stats(test_id);
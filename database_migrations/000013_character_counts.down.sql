@@ -0,0 +1,4 @@
+ALTER TABLE test_history DROP COLUMN missed_chars;
+ALTER TABLE test_history DROP COLUMN extra_chars;
+ALTER TABLE test_history DROP COLUMN incorrect_chars;
+ALTER TABLE test_history DROP COLUMN correct_chars;
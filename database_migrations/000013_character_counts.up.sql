@@ -0,0 +1,4 @@
+ALTER TABLE test_history ADD COLUMN correct_chars INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE test_history ADD COLUMN incorrect_chars INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE test_history ADD COLUMN extra_chars INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE test_history ADD COLUMN missed_chars INTEGER NOT NULL DEFAULT 0;
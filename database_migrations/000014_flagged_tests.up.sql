@@ -0,0 +1 @@
+ALTER TABLE test_history ADD COLUMN flagged TEXT NOT NULL DEFAULT '';
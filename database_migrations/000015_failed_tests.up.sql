@@ -0,0 +1 @@
+ALTER TABLE test_history ADD COLUMN failed BOOLEAN NOT NULL DEFAULT 0;
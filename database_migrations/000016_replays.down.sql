@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_replays_test_id;
+DROP INDEX IF EXISTS idx_replays_user_seed;
+DROP TABLE IF EXISTS replays;
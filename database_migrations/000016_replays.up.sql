@@ -0,0 +1,13 @@
+CREATE TABLE replays (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    test_id INTEGER NOT NULL,
+    seed TEXT NOT NULL,
+    word_input BOOLEAN NOT NULL DEFAULT 0,
+    keys TEXT NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY(test_id) REFERENCES test_history(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_replays_user_seed ON replays(user_id, seed);
+CREATE INDEX idx_replays_test_id ON replays(test_id);
@@ -16,6 +16,7 @@ require (
 	github.com/spf13/cobra v1.8.1
 	golang.org/x/crypto v0.50.0
 	golang.org/x/term v0.42.0
+	golang.org/x/text v0.36.0
 	modernc.org/sqlite v1.50.0
 )
 
@@ -0,0 +1,186 @@
+package words
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// passageTarget is the length a passage grows to before it may end at
+	// the next sentence. passageMax cuts overlong sentences at a word.
+	passageTarget = 300
+	passageMax    = 600
+)
+
+// Book is a long text split into passages that are typed one at a time.
+type Book struct {
+	Name     string
+	Passages []string
+}
+
+var (
+	markdownImage   = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink    = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownRule    = regexp.MustCompile(`^([-*_]\s*){3,}$`)
+	markdownList    = regexp.MustCompile(`^([-*+]|\d+[.)])\s+`)
+	htmlTag         = regexp.MustCompile(`<[^>]+>`)
+	typographicText = strings.NewReplacer(
+		"‘", "'", "’", "'", "“", "\"", "”", "\"",
+		"—", " - ", "–", "-", "…", "...", "\u00a0", " ",
+	)
+)
+
+// ListBooks returns the names of the .txt and .md files in dir, sorted. A
+// missing directory has no books.
+func ListBooks(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".txt" || ext == ".md") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// LoadBook reads a plain text or Markdown file and splits it into passages.
+// Markdown formatting is dropped and typographic punctuation is replaced with
+// what can be typed on a regular keyboard.
+func LoadBook(path string) (Book, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Book{}, err
+	}
+	if !utf8.Valid(data) {
+		return Book{}, fmt.Errorf("%s: not valid UTF-8", path)
+	}
+
+	markdown := strings.ToLower(filepath.Ext(path)) == ".md"
+	book := Book{
+		Name:     filepath.Base(path),
+		Passages: splitPassages(bookParagraphs(string(data), markdown)),
+	}
+	if len(book.Passages) == 0 {
+		return Book{}, fmt.Errorf("%s: no text to type", path)
+	}
+
+	return book, nil
+}
+
+// bookParagraphs turns the raw file into paragraphs of plain, single-spaced
+// text. Project Gutenberg licence headers and footers are left out.
+func bookParagraphs(text string, markdown bool) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if start := strings.Index(text, "*** START OF"); start >= 0 {
+		if end := strings.Index(text[start:], "\n"); end >= 0 {
+			text = text[start+end:]
+		}
+	}
+	if end := strings.Index(text, "*** END OF"); end >= 0 {
+		text = text[:end]
+	}
+
+	var paragraphs []string
+	var current []string
+	inFence := false
+	flush := func() {
+		if paragraph := strings.Join(strings.Fields(strings.Join(current, " ")), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+
+		if markdown {
+			if strings.HasPrefix(line, "```") {
+				inFence = !inFence
+				flush()
+				continue
+			}
+			if inFence {
+				continue
+			}
+			line = stripMarkdown(line)
+		}
+
+		if line == "" {
+			flush()
+			continue
+		}
+		current = append(current, typographicText.Replace(line))
+	}
+	flush()
+
+	return paragraphs
+}
+
+func stripMarkdown(line string) string {
+	if markdownRule.MatchString(line) {
+		return ""
+	}
+	line = strings.TrimLeft(line, "#>")
+	line = strings.TrimSpace(line)
+	line = markdownList.ReplaceAllString(line, "")
+	line = markdownImage.ReplaceAllString(line, "$1")
+	line = markdownLink.ReplaceAllString(line, "$1")
+	line = htmlTag.ReplaceAllString(line, "")
+	return strings.NewReplacer("**", "", "__", "", "*", "", "`", "").Replace(line)
+}
+
+// splitPassages packs sentences into passages of roughly passageTarget runes.
+// Paragraphs flow into each other so short ones don't become tiny passages.
+func splitPassages(paragraphs []string) []string {
+	var passages []string
+	var current []rune
+	flush := func() {
+		if passage := strings.TrimSpace(string(current)); passage != "" {
+			passages = append(passages, passage)
+		}
+		current = current[:0]
+	}
+
+	for _, word := range strings.Fields(strings.Join(paragraphs, " ")) {
+		runes := []rune(word)
+		if len(current) > 0 && len(current)+1+len(runes) > passageMax {
+			flush()
+		}
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+		current = append(current, runes...)
+
+		if len(current) >= passageTarget && endsSentence(word) {
+			flush()
+		}
+	}
+	flush()
+
+	return passages
+}
+
+func endsSentence(word string) bool {
+	word = strings.TrimRightFunc(word, func(r rune) bool {
+		return r == '"' || r == '\'' || r == ')'
+	})
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return unicode.Is(unicode.Sentence_Terminal, r)
+}
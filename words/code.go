@@ -0,0 +1,67 @@
+package words
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+)
+
+//go:embed embeds/code-snippets.json
+var codeSnippets string
+
+// CodeSnippet is a multi-line piece of source code. Indentation uses tabs and
+// lines are separated by '\n', both of which are typed like any other key.
+type CodeSnippet struct {
+	Id       int
+	Language string
+	Code     string
+}
+
+type CodeList struct {
+	MetaData MetaData
+	Snippets []CodeSnippet
+}
+
+// CodeLanguages returns the languages that have at least one snippet.
+func CodeLanguages() []string {
+	seen := make(map[string]bool)
+	var languages []string
+	for _, snippet := range DefaultCorpus().code.Snippets {
+		if !seen[snippet.Language] {
+			seen[snippet.Language] = true
+			languages = append(languages, snippet.Language)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// GenerateSnippet picks a random snippet in the given language, or in any
+// language when none of the snippets match.
+func (gen *WordGenerator) GenerateSnippet(language string) CodeSnippet {
+	var snippets []CodeSnippet
+	for _, snippet := range gen.corpus.code.Snippets {
+		if snippet.Language == language {
+			snippets = append(snippets, snippet)
+		}
+	}
+	if len(snippets) == 0 {
+		snippets = gen.corpus.code.Snippets
+	}
+	if len(snippets) == 0 {
+		return CodeSnippet{}
+	}
+
+	return snippets[gen.random().IntN(len(snippets))]
+}
+
+func parseCodeList() CodeList {
+	var codeList CodeList
+	err := json.Unmarshal([]byte(codeSnippets), &codeList)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return codeList
+}
@@ -0,0 +1,60 @@
+package words
+
+import (
+	"maps"
+	"sync"
+)
+
+// Corpus is the parsed text generators draw from. It is never modified once
+// built, so every session can share it; all state that changes while text is
+// generated, including the random source, lives on the WordGenerator.
+type Corpus struct {
+	lists  map[string]WordList
+	quotes map[string]QuoteList
+	code   CodeList
+}
+
+var (
+	embeddedOnce   sync.Once
+	embeddedCorpus *Corpus
+
+	corpusMu      sync.Mutex
+	currentCorpus *Corpus
+)
+
+// embedded parses the lists, quotes and snippets built into the binary. It
+// only does so once per process.
+func embedded() *Corpus {
+	embeddedOnce.Do(func() {
+		embeddedCorpus = &Corpus{
+			lists:  addEmbededSources(make(map[string]WordList, 0)),
+			quotes: addEmbededQuoteSources(make(map[string]QuoteList, 0)),
+			code:   parseCodeList(),
+		}
+	})
+	return embeddedCorpus
+}
+
+// DefaultCorpus returns the built-in text together with every registered
+// user list. Registering a list makes later calls return a new corpus;
+// generators holding the old one are unaffected.
+func DefaultCorpus() *Corpus {
+	corpusMu.Lock()
+	defer corpusMu.Unlock()
+
+	if currentCorpus == nil {
+		base := embedded()
+		currentCorpus = &Corpus{
+			lists:  addUserSources(maps.Clone(base.lists)),
+			quotes: base.quotes,
+			code:   base.code,
+		}
+	}
+	return currentCorpus
+}
+
+func invalidateCorpus() {
+	corpusMu.Lock()
+	defer corpusMu.Unlock()
+	currentCorpus = nil
+}
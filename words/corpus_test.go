@@ -0,0 +1,86 @@
+package words
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentGenerators drives many generators at once, the way SSH
+// sessions do under `serve`. Run with -race to catch shared mutable state.
+func TestConcurrentGenerators(t *testing.T) {
+	corpus := DefaultCorpus()
+	before := make(map[string][]string)
+	for name, list := range corpus.lists {
+		before[name] = slices.Clone(list.Words)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			gen := NewGenerator()
+			gen.Count = 100
+			gen.Punctuation = i%2 == 0
+			gen.Frequency = Frequency(i % len(FrequencyNames))
+			gen.Seed(uint64(i))
+
+			list := Languages[i%len(Languages)].List
+			for j := 0; j < 20; j++ {
+				gen.Generate(list)
+				gen.StartStream(list)
+				gen.ExtendStream(50)
+				gen.GeneratePractice(list, []string{"th", "er"})
+				gen.GenerateQuote("English quotes", QuoteLength(j%len(QuoteLengthNames)))
+				gen.GenerateSnippet("Go")
+			}
+		}(i)
+	}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			words := []string{"alpha", "beta", "gamma"}
+			list := WordList{MetaData: MetaData{Name: fmt.Sprintf("concurrent-%d", i)}, Words: words}
+			if err := RegisterSource(list); err != nil {
+				t.Error(err)
+			}
+			words[0] = "changed"
+
+			gen := NewGenerator()
+			gen.Count = 30
+			gen.Generate(list.MetaData.Name)
+			ListNames()
+			CodeLanguages()
+		}(i)
+	}
+
+	wg.Wait()
+
+	for name, words := range before {
+		if !slices.Equal(corpus.lists[name].Words, words) {
+			t.Errorf("list %q was modified while generating", name)
+		}
+	}
+
+	registered := DefaultCorpus().lists["concurrent-0"].Words
+	if !slices.Equal(registered, []string{"alpha", "beta", "gamma"}) {
+		t.Errorf("registered lists should not alias the caller's slice, got %v", registered)
+	}
+}
+
+func TestEmbeddedCorpusParsedOnce(t *testing.T) {
+	if embedded() != embedded() {
+		t.Error("the embedded corpus should be parsed once and shared")
+	}
+
+	first, second := NewGenerator(), NewGenerator()
+	if first.corpus != second.corpus {
+		t.Error("generators should share the corpus until a list is registered")
+	}
+}
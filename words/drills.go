@@ -0,0 +1,87 @@
+package words
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// numberChance is how often a word is replaced with a digit group when
+	// Numbers is on, symbolChance how often one is dressed in programming
+	// symbols when Symbols is on.
+	numberChance = 0.15
+	symbolChance = 0.2
+)
+
+var symbolForms = []string{
+	"(%s)", "[%s]", "{%s}", "<%s>", "%s()", "%s[i]", "$%s", "@%s",
+	"#%s", "&%s", "*%s", "%s;", "%s:", "!%s", "%s++", "__%s__",
+}
+
+var symbolOperators = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "->", "=>", "+=", "-=", "*", "/", "%", "^", "|", "=",
+}
+
+// drill mixes digit groups and programming symbols into a text's words. The
+// result is a new slice, words is left alone.
+func (gen *WordGenerator) drill(words []string) []string {
+	drilled := make([]string, len(words))
+	for i, word := range words {
+		drilled[i] = gen.drillWord(word)
+	}
+	return drilled
+}
+
+// drillWord leaves word alone while a key filter is active, as digits and
+// symbols are keys the filter doesn't allow.
+func (gen *WordGenerator) drillWord(word string) string {
+	if gen.KeyFilter.Active() {
+		return word
+	}
+	if gen.Numbers && gen.random().Float64() < numberChance {
+		return gen.numberGroup()
+	}
+	if gen.Symbols && gen.random().Float64() < symbolChance {
+		return gen.symbolWord(word)
+	}
+	return word
+}
+
+// numberGroup returns a year, a price, a phone-like sequence, a percentage
+// or a plain count.
+func (gen *WordGenerator) numberGroup() string {
+	rng := gen.random()
+	switch rng.IntN(5) {
+	case 0:
+		return fmt.Sprintf("%d", 1900+rng.IntN(131))
+	case 1:
+		return fmt.Sprintf("$%d.%02d", rng.IntN(1000), rng.IntN(100))
+	case 2:
+		return fmt.Sprintf("%03d-%03d-%04d", rng.IntN(1000), rng.IntN(1000), rng.IntN(10000))
+	case 3:
+		return fmt.Sprintf("%d%%", rng.IntN(101))
+	default:
+		return fmt.Sprintf("%d", rng.IntN(10000))
+	}
+}
+
+// symbolWord wraps word in brackets or sigils, joins it to another word with
+// an underscore or puts an operator between the two.
+func (gen *WordGenerator) symbolWord(word string) string {
+	rng := gen.random()
+	switch rng.IntN(4) {
+	case 0:
+		return word + "_" + gen.randomWord()
+	case 1:
+		return word + " " + symbolOperators[rng.IntN(len(symbolOperators))] + " " + gen.randomWord()
+	default:
+		return fmt.Sprintf(symbolForms[rng.IntN(len(symbolForms))], word)
+	}
+}
+
+// isPlainWord reports whether word is only letters, so that punctuation
+// isn't stacked onto digit groups and symbols.
+func isPlainWord(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }) < 0
+}
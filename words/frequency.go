@@ -0,0 +1,139 @@
+package words
+
+import (
+	"math/rand/v2"
+	"sort"
+)
+
+// Frequency selects which part of a ranked list a test draws from. Words are
+// sampled in proportion to how common they are, except in the rare tier.
+type Frequency int
+
+// There are no tiers past the top 200: the longest shipped list has 860
+// words, so anything wider would draw from all of it.
+const (
+	FrequencyAll Frequency = iota
+	FrequencyTop200
+	FrequencyRare
+)
+
+var FrequencyNames = []string{"all", "top200", "rare"}
+
+var frequencyTiers = map[Frequency]int{
+	FrequencyTop200: 200,
+}
+
+// rareFrom is the rank the rare tier starts at. Lists shorter than twice
+// this keep their more common half out of the rare tier instead.
+const rareFrom = 200
+
+func ParseFrequency(name string) Frequency {
+	for i, frequencyName := range FrequencyNames {
+		if frequencyName == name {
+			return Frequency(i)
+		}
+	}
+	return FrequencyAll
+}
+
+func (f Frequency) String() string {
+	if int(f) < 0 || int(f) >= len(FrequencyNames) {
+		return FrequencyNames[FrequencyAll]
+	}
+	return FrequencyNames[f]
+}
+
+// sampler draws words with replacement. A nil cumulative slice means every
+// word is equally likely.
+type sampler struct {
+	words      []string
+	cumulative []float64
+}
+
+// IsRanked reports whether the list carries frequency data.
+func (list WordList) IsRanked() bool {
+	if len(list.Words) == 0 {
+		return false
+	}
+	return list.MetaData.Ranked || len(list.MetaData.Counts) == len(list.Words)
+}
+
+// byFrequency returns the words ordered from most to least common along with
+// their weights. Counts win over rank order when both are present; otherwise
+// a word's weight follows Zipf's law, 1/rank.
+func (list WordList) byFrequency() ([]string, []float64) {
+	words := append([]string(nil), list.Words...)
+	weights := make([]float64, len(words))
+
+	if counts := list.MetaData.Counts; len(counts) == len(words) {
+		order := make([]int, len(words))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return counts[order[a]] > counts[order[b]] })
+
+		for i, idx := range order {
+			words[i] = list.Words[idx]
+			weights[i] = float64(counts[idx])
+		}
+		return words, weights
+	}
+
+	for i := range words {
+		weights[i] = 1 / float64(i+1)
+	}
+	return words, weights
+}
+
+// HasTier reports whether frequency picks out part of the list. A tier at
+// least as long as the list would draw from all of it, so it isn't offered.
+func (list WordList) HasTier(frequency Frequency) bool {
+	size, ok := frequencyTiers[frequency]
+	return !ok || size < len(list.Words)
+}
+
+func (list WordList) sampler(frequency Frequency) sampler {
+	words, weights := list.byFrequency()
+
+	if frequency == FrequencyRare {
+		start := min(rareFrom, len(words)/2)
+		return sampler{words: words[start:]}
+	}
+
+	if size, ok := frequencyTiers[frequency]; ok && size < len(words) {
+		words, weights = words[:size], weights[:size]
+	}
+
+	cumulative := make([]float64, len(weights))
+	total := 0.0
+	for i, weight := range weights {
+		total += weight
+		cumulative[i] = total
+	}
+
+	return sampler{words: words, cumulative: cumulative}
+}
+
+func (s sampler) pick(rng *rand.Rand) string {
+	if len(s.cumulative) == 0 {
+		return s.words[rng.IntN(len(s.words))]
+	}
+
+	target := rng.Float64() * s.cumulative[len(s.cumulative)-1]
+	idx := sort.SearchFloat64s(s.cumulative, target)
+	return s.words[min(idx, len(s.words)-1)]
+}
+
+// sample draws n words, redrawing a few times to avoid the same word twice in
+// a row, which the most common words would otherwise do often.
+func (s sampler) sample(rng *rand.Rand, n int) []string {
+	words := make([]string, 0, n)
+	for len(words) < n {
+		word := s.pick(rng)
+		for try := 0; try < 3 && len(words) > 0 && word == words[len(words)-1]; try++ {
+			word = s.pick(rng)
+		}
+		words = append(words, word)
+	}
+	return words
+}
@@ -0,0 +1,158 @@
+package words
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minFilteredWords is the smallest pool a key filter may leave. Smaller pools
+// are topped up with letter groups made from the allowed keys.
+const minFilteredWords = 20
+
+// KeyFilter restricts a test to words that can be typed with a set of keys.
+// The zero value lets every word through.
+type KeyFilter struct {
+	// Name is one of KeyFilterNames.
+	Name string
+	// Keys holds the allowed letters, lowercase and sorted.
+	Keys string
+}
+
+const CustomKeyFilter = "custom"
+
+var KeyFilterNames = []string{"none", "home", "left", "right", "top", CustomKeyFilter}
+
+// keyFilterPresets are the QWERTY keys of each region.
+var keyFilterPresets = map[string]string{
+	"home":  "asdfghjkl",
+	"left":  "qwertasdfgzxcvb",
+	"right": "yuiophjklnm",
+	"top":   "qwertyuiop",
+}
+
+var keyFilterLabels = map[string]string{
+	"home":  "home row",
+	"left":  "left hand",
+	"right": "right hand",
+	"top":   "top row",
+}
+
+// ParseKeyFilter returns the filter for a preset name, or for customKeys when
+// name is "custom". Unknown names and empty custom sets filter nothing.
+func ParseKeyFilter(name, customKeys string) KeyFilter {
+	if name == CustomKeyFilter {
+		keys := NormalizeKeys(customKeys)
+		if keys == "" {
+			return KeyFilter{}
+		}
+		return KeyFilter{Name: CustomKeyFilter, Keys: keys}
+	}
+	if keys, ok := keyFilterPresets[name]; ok {
+		return KeyFilter{Name: name, Keys: NormalizeKeys(keys)}
+	}
+	return KeyFilter{}
+}
+
+// NormalizeKeys keeps the letters of keys, lowercased, sorted and without
+// repeats.
+func NormalizeKeys(keys string) string {
+	seen := make(map[rune]bool)
+	var letters []rune
+	for _, r := range strings.ToLower(keys) {
+		if unicode.IsLetter(r) && !seen[r] {
+			seen[r] = true
+			letters = append(letters, r)
+		}
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return string(letters)
+}
+
+func (f KeyFilter) Active() bool {
+	return f.Keys != ""
+}
+
+// Label is how the filter is shown on the test screen, or empty when it is
+// off.
+func (f KeyFilter) Label() string {
+	if !f.Active() {
+		return ""
+	}
+	if label, ok := keyFilterLabels[f.Name]; ok {
+		return label
+	}
+	return "keys " + f.Keys
+}
+
+// String is the filter as stored with a test result, e.g. "home" or
+// "custom:asdf".
+func (f KeyFilter) String() string {
+	if !f.Active() {
+		return ""
+	}
+	if f.Name == CustomKeyFilter {
+		return CustomKeyFilter + ":" + f.Keys
+	}
+	return f.Name
+}
+
+// Allows reports whether every letter of word is one of the filter's keys.
+func (f KeyFilter) Allows(word string) bool {
+	if !f.Active() {
+		return true
+	}
+	for _, r := range strings.ToLower(word) {
+		if !strings.ContainsRune(f.Keys, r) {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// filter keeps the words of list that f allows, along with their ranks and
+// counts. When too few are left, letter groups made from the keys fill the
+// pool up and the list is no longer ranked.
+func (list WordList) filter(f KeyFilter) WordList {
+	if !f.Active() {
+		return list
+	}
+
+	filtered := WordList{MetaData: MetaData{Name: list.MetaData.Name, Ranked: list.MetaData.Ranked}}
+	hasCounts := len(list.MetaData.Counts) == len(list.Words)
+	for i, word := range list.Words {
+		if !f.Allows(word) {
+			continue
+		}
+		filtered.Words = append(filtered.Words, word)
+		if hasCounts {
+			filtered.MetaData.Counts = append(filtered.MetaData.Counts, list.MetaData.Counts[i])
+		}
+	}
+
+	if len(filtered.Words) < minFilteredWords {
+		filtered.Words = append(filtered.Words, keyGroups(f.Keys, minFilteredWords-len(filtered.Words))...)
+		filtered.MetaData.Ranked = false
+		filtered.MetaData.Counts = nil
+	}
+	filtered.MetaData.Size = len(filtered.Words)
+
+	return filtered
+}
+
+// keyGroups makes n letter groups of two to five keys. They follow a fixed
+// pattern rather than the generator's random source so that a filtered list
+// is the same every time it is built.
+func keyGroups(keys string, n int) []string {
+	letters := []rune(keys)
+	groups := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		length := 2 + i%4
+		group := make([]rune, length)
+		for j := range group {
+			group[j] = letters[(i*7+j*3+j*i)%len(letters)]
+		}
+		groups = append(groups, string(group))
+	}
+	return groups
+}
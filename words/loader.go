@@ -0,0 +1,242 @@
+package words
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const DefaultList = "Common words"
+
+var (
+	userSourcesMu sync.RWMutex
+	userSources   = make(map[string]WordList)
+)
+
+// LoadUserSources registers every .json and .txt word list found in dir.
+// A missing directory is not an error. Lists that fail to load or validate
+// are skipped and reported together in the returned error.
+func LoadUserSources(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".json" && ext != ".txt" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		list, err := LoadWordList(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := RegisterSource(list); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// LoadWordList reads a word list in the WordList JSON shape, or a plain text
+// file with one word per line named after the file.
+func LoadWordList(path string) (WordList, error) {
+	list, err := ReadWordList(path)
+	if err != nil {
+		return WordList{}, err
+	}
+
+	if err := ValidateWordList(list); err != nil {
+		return WordList{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return list, nil
+}
+
+// ReadWordList is LoadWordList without the validation, for tools that want to
+// report what is wrong with a list rather than skip it.
+func ReadWordList(path string) (WordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return WordList{}, err
+	}
+	defer file.Close()
+
+	var list WordList
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		if err := json.NewDecoder(file).Decode(&list); err != nil {
+			return WordList{}, fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		list.MetaData.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			word := strings.TrimSpace(scanner.Text())
+			if word != "" {
+				list.Words = append(list.Words, word)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return WordList{}, fmt.Errorf("%s: %w", path, err)
+		}
+		list.MetaData.Size = len(list.Words)
+	}
+
+	return list, nil
+}
+
+// ValidateWordList returns the first problem that keeps list from being used.
+// Duplicate words are allowed; see CheckWordList.
+func ValidateWordList(list WordList) error {
+	if problems := wordListProblems(list, false); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// CheckWordList returns every problem with list, including duplicate words,
+// which only skew how often a word comes up.
+func CheckWordList(list WordList) []error {
+	return wordListProblems(list, true)
+}
+
+func wordListProblems(list WordList, duplicates bool) []error {
+	var problems []error
+	name := list.MetaData.Name
+	if strings.TrimSpace(name) == "" {
+		problems = append(problems, fmt.Errorf("word list has no name"))
+	}
+	if len(list.Words) == 0 {
+		problems = append(problems, fmt.Errorf("word list %q has no words", name))
+	}
+	if size := list.MetaData.Size; size != 0 && size != len(list.Words) {
+		problems = append(problems, fmt.Errorf("word list %q declares %d words but has %d", name, size, len(list.Words)))
+	}
+
+	if counts := list.MetaData.Counts; len(counts) > 0 {
+		if len(counts) != len(list.Words) {
+			problems = append(problems, fmt.Errorf("word list %q has %d counts for %d words", name, len(counts), len(list.Words)))
+		}
+		for i, count := range counts {
+			if count <= 0 {
+				problems = append(problems, fmt.Errorf("word list %q has a non-positive count at index %d", name, i))
+			}
+		}
+	}
+
+	seen := make(map[string]int, len(list.Words))
+	for i, word := range list.Words {
+		if word == "" {
+			problems = append(problems, fmt.Errorf("word list %q has an empty word at index %d", name, i))
+			continue
+		}
+		if !utf8.ValidString(word) {
+			problems = append(problems, fmt.Errorf("word list %q has invalid UTF-8 at index %d", name, i))
+			continue
+		}
+		if strings.IndexFunc(word, unicode.IsSpace) >= 0 {
+			problems = append(problems, fmt.Errorf("word list %q has whitespace in %q", name, word))
+		} else if r, ok := untypeableRune(word); ok {
+			problems = append(problems, fmt.Errorf("word list %q has a character that can't be typed (%U) in %q", name, r, word))
+		}
+
+		if first, ok := seen[word]; ok && duplicates {
+			problems = append(problems, fmt.Errorf("word list %q repeats %q at index %d, first seen at %d", name, word, i, first))
+		} else if !ok {
+			seen[word] = i
+		}
+	}
+
+	return problems
+}
+
+// untypeableRune finds a rune no key press produces on its own: control and
+// formatting characters, and combining marks that only decorate the rune
+// before them.
+func untypeableRune(word string) (rune, bool) {
+	for _, r := range word {
+		if !unicode.IsPrint(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// RegisterSource makes a validated word list available to every generator
+// created afterwards. The list is copied, so the caller may reuse its slices.
+func RegisterSource(list WordList) error {
+	if err := ValidateWordList(list); err != nil {
+		return err
+	}
+
+	name := list.MetaData.Name
+	for _, lang := range Languages {
+		if strings.EqualFold(name, lang.List) {
+			return fmt.Errorf("word list %q clashes with a built-in list", name)
+		}
+	}
+
+	list.Words = slices.Clone(list.Words)
+	list.MetaData.Counts = slices.Clone(list.MetaData.Counts)
+
+	userSourcesMu.Lock()
+	userSources[name] = list
+	userSourcesMu.Unlock()
+
+	invalidateCorpus()
+
+	return nil
+}
+
+// ListNames returns the built-in lists followed by the user lists, sorted by name.
+func ListNames() []string {
+	var names []string
+	for _, lang := range Languages {
+		names = append(names, lang.List)
+	}
+
+	userSourcesMu.RLock()
+	defer userSourcesMu.RUnlock()
+
+	var userNames []string
+	for name := range userSources {
+		userNames = append(userNames, name)
+	}
+	sort.Strings(userNames)
+
+	return append(names, userNames...)
+}
+
+func addUserSources(sources map[string]WordList) map[string]WordList {
+	userSourcesMu.RLock()
+	defer userSourcesMu.RUnlock()
+
+	for name, list := range userSources {
+		sources[name] = list
+	}
+
+	return sources
+}
@@ -0,0 +1,66 @@
+package words
+
+import "strings"
+
+// GeneratePractice builds text from the words of a list that contain any of
+// the given bigrams. Bigrams are ordered weakest first and earlier ones weigh
+// more, so the worst pair shows up most often. When no word matches, it falls
+// back to Generate.
+func (gen *WordGenerator) GeneratePractice(wordListName string, bigrams []string) []rune {
+	var pool []string
+	var cumulative []float64
+	total := 0.0
+	for _, word := range gen.list(wordListName).Words {
+		lower := strings.ToLower(word)
+		score := 0.0
+		for i, bigram := range bigrams {
+			if strings.Contains(lower, bigram) {
+				score += float64(len(bigrams) - i)
+			}
+		}
+		if score > 0 {
+			total += score
+			pool = append(pool, word)
+			cumulative = append(cumulative, total)
+		}
+	}
+
+	if len(pool) == 0 {
+		return gen.Generate(wordListName)
+	}
+
+	practice := sampler{words: pool, cumulative: cumulative}
+	gen.weighted = &practice
+
+	return []rune(strings.Join(practice.sample(gen.random(), gen.Count), " "))
+}
+
+// GenerateRepeats shuffles each of words in repeats times. The same word is
+// kept from coming up twice in a row where the mix allows it.
+func (gen *WordGenerator) GenerateRepeats(words []string, repeats int) []rune {
+	var drill []string
+	for _, word := range words {
+		for i := 0; i < repeats; i++ {
+			drill = append(drill, word)
+		}
+	}
+
+	rng := gen.random()
+	rng.Shuffle(len(drill), func(i, j int) { drill[i], drill[j] = drill[j], drill[i] })
+	for i := 1; i < len(drill); i++ {
+		if drill[i] != drill[i-1] {
+			continue
+		}
+		repeated := drill[i]
+		for j := i + 1; j < len(drill); j++ {
+			fitsHere := drill[j] != repeated && (j == i+1 || drill[j] != drill[i+1])
+			fitsThere := (j+1 == len(drill) || drill[j+1] != repeated) && (j == i+1 || drill[j-1] != repeated)
+			if fitsHere && fitsThere {
+				drill[i], drill[j] = drill[j], drill[i]
+				break
+			}
+		}
+	}
+
+	return []rune(strings.Join(drill, " "))
+}
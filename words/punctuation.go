@@ -0,0 +1,194 @@
+package words
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PunctuationProfile sets how often each kind of punctuation shows up. The
+// separator weights are the chance of that separator following a word and
+// may add up to at most 1; whatever is left over is a plain space. The other
+// weights apply to single words or to quoted phrases.
+type PunctuationProfile struct {
+	Comma       float64
+	Period      float64
+	Question    float64
+	Exclamation float64
+	Semicolon   float64
+	Colon       float64
+	// Dash is an em-dash, typed as a hyphen with a space on either side.
+	Dash        float64
+	Parenthesis float64
+	Quote       float64
+	// NestedQuote is the chance a quoted phrase holds a 'single-quoted' one.
+	NestedQuote float64
+	// Contraction is the chance an English word is swapped for a contraction.
+	Contraction float64
+	// Hyphenated is the chance a word is joined to the next with a hyphen.
+	Hyphenated float64
+}
+
+const CustomPunctuation = "custom"
+
+var PunctuationProfileNames = []string{"light", "normal", "heavy", CustomPunctuation}
+
+var PunctuationProfiles = map[string]PunctuationProfile{
+	"light": {
+		Comma: 0.05, Period: 0.06, Question: 0.01,
+		Quote: 0.005, Contraction: 0.02, Hyphenated: 0.005,
+	},
+	"normal": {
+		Comma: 0.08, Period: 0.06, Question: 0.01, Exclamation: 0.005,
+		Semicolon: 0.005, Colon: 0.005, Dash: 0.005, Parenthesis: 0.01,
+		Quote: 0.015, NestedQuote: 0.2, Contraction: 0.04, Hyphenated: 0.015,
+	},
+	"heavy": {
+		Comma: 0.12, Period: 0.08, Question: 0.03, Exclamation: 0.02,
+		Semicolon: 0.03, Colon: 0.03, Dash: 0.03, Parenthesis: 0.04,
+		Quote: 0.05, NestedQuote: 0.35, Contraction: 0.08, Hyphenated: 0.04,
+	},
+}
+
+// PunctuationWeightNames are the keys custom weights are stored under, in
+// the order they are shown.
+var PunctuationWeightNames = []string{
+	"comma", "period", "question", "exclamation", "semicolon", "colon",
+	"dash", "parenthesis", "quote", "nested_quote", "contraction", "hyphenated",
+}
+
+var contractions = []string{
+	"don't", "it's", "I'm", "can't", "won't", "that's", "you're", "we're",
+	"they're", "isn't", "doesn't", "didn't", "I've", "let's", "there's",
+	"wasn't", "you'll", "she's", "he's", "I'd", "couldn't", "wouldn't",
+}
+
+// ParsePunctuationProfile returns a named profile. The custom profile is read
+// from weights; unknown names get the normal profile.
+func ParsePunctuationProfile(name string, weights map[string]float64) (PunctuationProfile, error) {
+	if name == CustomPunctuation {
+		return CustomPunctuationProfile(weights)
+	}
+	if profile, ok := PunctuationProfiles[name]; ok {
+		return profile, nil
+	}
+	return PunctuationProfiles["normal"], nil
+}
+
+// PunctuationProfileName returns name if it is a known profile, and "normal"
+// otherwise.
+func PunctuationProfileName(name string) string {
+	for _, profileName := range PunctuationProfileNames {
+		if profileName == name {
+			return name
+		}
+	}
+	return "normal"
+}
+
+func CustomPunctuationProfile(weights map[string]float64) (PunctuationProfile, error) {
+	var profile PunctuationProfile
+	fields := profile.fields()
+	for name, weight := range weights {
+		field, ok := fields[name]
+		if !ok {
+			return PunctuationProfile{}, fmt.Errorf("unknown punctuation weight %q", name)
+		}
+		*field = weight
+	}
+
+	if err := profile.Validate(); err != nil {
+		return PunctuationProfile{}, err
+	}
+	return profile, nil
+}
+
+// Weights returns the profile keyed by PunctuationWeightNames.
+func (p PunctuationProfile) Weights() map[string]float64 {
+	weights := make(map[string]float64, len(PunctuationWeightNames))
+	for name, field := range p.fields() {
+		weights[name] = *field
+	}
+	return weights
+}
+
+func (p PunctuationProfile) Validate() error {
+	for name, field := range p.fields() {
+		if *field < 0 || *field > 1 {
+			return fmt.Errorf("punctuation weight %q must be between 0 and 1", name)
+		}
+	}
+
+	if total := p.separatorTotal(); total > 1 {
+		return fmt.Errorf("punctuation separators add up to %.2f, more than 1", total)
+	}
+	return nil
+}
+
+func (p *PunctuationProfile) fields() map[string]*float64 {
+	return map[string]*float64{
+		"comma":        &p.Comma,
+		"period":       &p.Period,
+		"question":     &p.Question,
+		"exclamation":  &p.Exclamation,
+		"semicolon":    &p.Semicolon,
+		"colon":        &p.Colon,
+		"dash":         &p.Dash,
+		"parenthesis":  &p.Parenthesis,
+		"quote":        &p.Quote,
+		"nested_quote": &p.NestedQuote,
+		"contraction":  &p.Contraction,
+		"hyphenated":   &p.Hyphenated,
+	}
+}
+
+func (p PunctuationProfile) separatorTotal() float64 {
+	return p.Comma + p.Period + p.Question + p.Exclamation + p.Semicolon +
+		p.Colon + p.Dash + p.Parenthesis + p.Quote
+}
+
+func (gen *WordGenerator) profile() PunctuationProfile {
+	if gen.PunctuationProfile == (PunctuationProfile{}) {
+		return PunctuationProfiles["normal"]
+	}
+	return gen.PunctuationProfile
+}
+
+// punctuateWord applies the word-level constructs of the profile: swapping
+// in a contraction or joining the word to another with a hyphen.
+func (gen *WordGenerator) punctuateWord(word string) string {
+	if !isPlainWord(word) {
+		return word
+	}
+	profile := gen.profile()
+	roll := gen.random().Float64()
+
+	// Contractions are English and use keys a filter may not allow, as do
+	// hyphens.
+	contraction, hyphenated := profile.Contraction, profile.Hyphenated
+	if !gen.english || gen.KeyFilter.Active() {
+		contraction = 0
+	}
+	if gen.KeyFilter.Active() {
+		hyphenated = 0
+	}
+
+	switch {
+	case roll < contraction:
+		return contractions[gen.random().IntN(len(contractions))]
+	case roll < contraction+hyphenated:
+		return word + "-" + gen.randomWord()
+	default:
+		return word
+	}
+}
+
+// isEnglishList reports whether contractions make sense for a list. User
+// lists are assumed to be English.
+func isEnglishList(wordListName string) bool {
+	for _, lang := range Languages {
+		if strings.EqualFold(lang.List, wordListName) {
+			return lang.Name == "English"
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package words
+
+import (
+	_ "embed"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+//go:embed embeds/quotes-english.json
+var englishQuotes string
+
+type QuoteLength int
+
+const (
+	QuoteAll QuoteLength = iota
+	QuoteShort
+	QuoteMedium
+	QuoteLong
+)
+
+var QuoteLengthNames = []string{"all", "short", "medium", "long"}
+
+type QuoteMetaData struct {
+	Name string
+	Size int
+	// Groups holds the inclusive [min, max] character range of the short,
+	// medium and long buckets, in that order.
+	Groups [][2]int
+}
+
+type Quote struct {
+	Id     int
+	Text   string
+	Source string
+	Author string
+}
+
+type QuoteList struct {
+	MetaData QuoteMetaData
+	Quotes   []Quote
+}
+
+func (q Quote) Attribution() string {
+	switch {
+	case q.Author != "" && q.Source != "":
+		return q.Author + ", " + q.Source
+	case q.Author != "":
+		return q.Author
+	default:
+		return q.Source
+	}
+}
+
+func ParseQuoteLength(name string) QuoteLength {
+	for i, lengthName := range QuoteLengthNames {
+		if lengthName == name {
+			return QuoteLength(i)
+		}
+	}
+	return QuoteAll
+}
+
+func (l QuoteLength) String() string {
+	if int(l) < 0 || int(l) >= len(QuoteLengthNames) {
+		return QuoteLengthNames[QuoteAll]
+	}
+	return QuoteLengthNames[l]
+}
+
+func (list QuoteList) lengthOf(quote Quote) QuoteLength {
+	size := utf8.RuneCountInString(quote.Text)
+	for i, group := range list.MetaData.Groups {
+		if size >= group[0] && size <= group[1] {
+			return QuoteLength(i + 1)
+		}
+	}
+	return QuoteLong
+}
+
+func (list QuoteList) withLength(length QuoteLength) []Quote {
+	if length == QuoteAll {
+		return list.Quotes
+	}
+
+	var quotes []Quote
+	for _, quote := range list.Quotes {
+		if list.lengthOf(quote) == length {
+			quotes = append(quotes, quote)
+		}
+	}
+	return quotes
+}
+
+func (gen *WordGenerator) GenerateQuote(quoteListName string, length QuoteLength) Quote {
+	list := gen.corpus.quotes[quoteListName]
+	quotes := list.withLength(length)
+	if len(quotes) == 0 {
+		quotes = list.Quotes
+	}
+	if len(quotes) == 0 {
+		return Quote{}
+	}
+
+	return quotes[gen.random().IntN(len(quotes))]
+}
+
+func addEmbededQuoteSources(sources map[string]QuoteList) map[string]QuoteList {
+	var quoteList QuoteList
+	err := json.Unmarshal([]byte(englishQuotes), &quoteList)
+
+	if err != nil {
+		panic(err)
+	}
+
+	sources["English quotes"] = quoteList
+
+	return sources
+}
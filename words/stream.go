@@ -0,0 +1,61 @@
+package words
+
+import "math"
+
+// StartStream begins an open-ended text for timed tests and returns its first
+// gen.Count words. ExtendStream continues the same text, so a test can keep
+// asking for more for as long as it runs.
+func (gen *WordGenerator) StartStream(wordListName string) []rune {
+	list := gen.list(wordListName)
+	gen.english = isEnglishList(wordListName)
+	if list.IsRanked() {
+		weighted := list.sampler(gen.Frequency)
+		gen.weighted = &weighted
+	} else {
+		gen.currentPool = append([]string(nil), list.Words...)
+		gen.poolIndex = len(gen.currentPool)
+		gen.weighted = nil
+	}
+	gen.sentenceStart = true
+	gen.lastWord = ""
+
+	return gen.ExtendStream(gen.Count)
+}
+
+// ExtendStream returns the next n words of the stream. Every word is followed
+// by its separator, so chunks can be appended to each other as they are, and
+// a sentence left open by one chunk is carried on by the next.
+func (gen *WordGenerator) ExtendStream(n int) []rune {
+	var result []rune
+	for i := 0; i < n; i++ {
+		word := gen.randomWord()
+		for try := 0; try < 3 && word == gen.lastWord; try++ {
+			word = gen.randomWord()
+		}
+		gen.lastWord = word
+		word = gen.drillWord(word)
+
+		if !gen.Punctuation {
+			result = append(result, []rune(word)...)
+			result = append(result, ' ')
+			continue
+		}
+
+		word = gen.punctuateWord(word)
+		if gen.sentenceStart {
+			word = capitalizeFirst(word)
+			gen.sentenceStart = false
+		}
+		result = append(result, []rune(word)...)
+
+		// The stream has no last word, so never let the punctuation treat
+		// this one as the end of the text.
+		nextPunct := gen.getNextPunctuation(0, math.MaxInt32)
+		result = append(result, nextPunct...)
+		if containsEndPunct(nextPunct) {
+			gen.sentenceStart = true
+		}
+	}
+
+	return result
+}
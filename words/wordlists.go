@@ -0,0 +1,94 @@
+package words
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ListInfo describes a word list available to generators.
+type ListInfo struct {
+	Name string
+	// MetaName and Size are what the list's metadata declares; Words is how
+	// many words it really has.
+	MetaName string
+	Size     int
+	Words    int
+	Ranked   bool
+	BuiltIn  bool
+}
+
+// ListInfos describes every list in ListNames order.
+func ListInfos() []ListInfo {
+	corpus := DefaultCorpus()
+
+	var infos []ListInfo
+	for _, name := range ListNames() {
+		list, ok := corpus.lists[name]
+		if !ok {
+			continue
+		}
+		infos = append(infos, ListInfo{
+			Name:     name,
+			MetaName: list.MetaData.Name,
+			Size:     list.MetaData.Size,
+			Words:    len(list.Words),
+			Ranked:   list.IsRanked(),
+			BuiltIn:  isBuiltInList(name),
+		})
+	}
+
+	return infos
+}
+
+// FindList returns the list registered under name, ignoring case.
+func FindList(name string) (WordList, bool) {
+	for listName, list := range DefaultCorpus().lists {
+		if strings.EqualFold(listName, name) {
+			return list, true
+		}
+	}
+	return WordList{}, false
+}
+
+func isBuiltInList(name string) bool {
+	for _, lang := range Languages {
+		if lang.List == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportWordList turns plain text into a word list. Words are split on any
+// whitespace and kept in the order they first appear; repeats, and words with
+// characters that can't be typed, are dropped and counted in skipped. Text
+// that is ordered from the most common word down can be marked ranked.
+func ImportWordList(r io.Reader, name string, ranked bool) (list WordList, skipped int, err error) {
+	list.MetaData = MetaData{Name: name, Ranked: ranked}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		word := scanner.Text()
+		if _, bad := untypeableRune(word); bad || !utf8.ValidString(word) || seen[word] {
+			skipped++
+			continue
+		}
+		seen[word] = true
+		list.Words = append(list.Words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return WordList{}, 0, err
+	}
+	list.MetaData.Size = len(list.Words)
+
+	if err := ValidateWordList(list); err != nil {
+		return WordList{}, 0, fmt.Errorf("import %q: %w", name, err)
+	}
+
+	return list, skipped, nil
+}
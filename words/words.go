@@ -1,7 +1,7 @@
 package words
 
 import (
-	_ "embed"
+	"embed"
 	"encoding/json"
 	"math/rand/v2"
 	"strings"
@@ -9,50 +9,119 @@ import (
 	"unicode/utf8"
 )
 
-//TODO: Add quote test.
+//go:embed embeds/common-*.json
+var embededLists embed.FS
 
-//go:embed embeds/common-english.json
-var commonWords string
+type Language struct {
+	Name string
+	List string
+	// Code is the short name used in seed codes.
+	Code string
+	file string
+}
+
+var Languages = []Language{
+	{Name: "English", List: DefaultList, Code: "en", file: "common-english.json"},
+	{Name: "German", List: "German", Code: "de", file: "common-german.json"},
+	{Name: "French", List: "French", Code: "fr", file: "common-french.json"},
+	{Name: "Spanish", List: "Spanish", Code: "es", file: "common-spanish.json"},
+	{Name: "Portuguese", List: "Portuguese", Code: "pt", file: "common-portuguese.json"},
+	{Name: "Polish", List: "Polish", Code: "pl", file: "common-polish.json"},
+	{Name: "Russian", List: "Russian", Code: "ru", file: "common-russian.json"},
+}
 
 type MetaData struct {
-	Name string
-	Size int
+	Name string `json:"name"`
+	Size int    `json:"size"`
+	// Ranked marks lists whose words are ordered from most to least common.
+	Ranked bool `json:"ranked,omitempty"`
+	// Counts optionally holds how often each word occurs, parallel to Words.
+	Counts []int `json:"counts,omitempty"`
 }
 
 type WordList struct {
-	MetaData MetaData
-	Words    []string
+	MetaData MetaData `json:"metadata"`
+	Words    []string `json:"words"`
 }
 
+// WordGenerator samples text from a shared Corpus. Each session should use
+// its own generator: it carries the random source and the position in the
+// current text, none of which is safe to share.
 type WordGenerator struct {
 	Count       int
 	Punctuation bool
-	poolsJson   map[string]WordList
+	// PunctuationProfile weighs the punctuation used when Punctuation is
+	// set. The zero value uses the normal profile.
+	PunctuationProfile PunctuationProfile
+	// Numbers and Symbols mix digit groups and programming symbols into the
+	// text, see drillWord. They are off while KeyFilter is active.
+	Numbers bool
+	Symbols bool
+	// KeyFilter limits the text to words typeable with its keys.
+	KeyFilter   KeyFilter
+	Frequency   Frequency
+	corpus      *Corpus
 	currentPool []string
 	poolIndex   int
+	weighted    *sampler
+	rng         *rand.Rand
+	english     bool
+
+	// Stream state, see StartStream.
+	sentenceStart bool
+	lastWord      string
 }
 
 func NewGenerator() WordGenerator {
 	var gen WordGenerator
 	gen.Count = 300
-	gen.poolsJson = addEmbededSources(make(map[string]WordList, 0))
+	gen.corpus = DefaultCorpus()
+	gen.Seed(rand.Uint64())
 
 	return gen
 }
 
+// Seed resets the generator's random source. Two generators seeded with the
+// same value produce the same text for the same list and settings.
+func (gen *WordGenerator) Seed(seed uint64) {
+	gen.rng = rand.New(rand.NewPCG(seed, seed))
+}
+
+func (gen *WordGenerator) random() *rand.Rand {
+	if gen.rng == nil {
+		gen.Seed(rand.Uint64())
+	}
+	return gen.rng
+}
+
 func (gen *WordGenerator) Generate(wordListName string) []rune {
-	pool := gen.poolsJson[wordListName].Words
-	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	list := gen.list(wordListName)
+	gen.english = isEnglishList(wordListName)
 
 	wordsNeeded := gen.Count
 	if gen.Punctuation {
 		wordsNeeded = int(float64(gen.Count) * 0.8)
 	}
 
-	amount := min(wordsNeeded, len(pool))
-	words := pool[0:amount]
-	gen.currentPool = pool
-	gen.poolIndex = amount
+	var words []string
+	if list.IsRanked() {
+		weighted := list.sampler(gen.Frequency)
+		gen.weighted = &weighted
+		words = weighted.sample(gen.random(), wordsNeeded)
+	} else {
+		pool := append([]string(nil), list.Words...)
+		gen.random().Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+		amount := min(wordsNeeded, len(pool))
+		words = pool[0:amount]
+		gen.currentPool = pool
+		gen.poolIndex = amount
+		gen.weighted = nil
+	}
+
+	if gen.Numbers || gen.Symbols {
+		words = gen.drill(words)
+	}
 
 	if !gen.Punctuation {
 		return []rune(strings.Join(words, " "))
@@ -61,14 +130,22 @@ func (gen *WordGenerator) Generate(wordListName string) []rune {
 	return gen.generateWithPunctuation(words)
 }
 
+func (gen *WordGenerator) list(wordListName string) WordList {
+	list, ok := gen.corpus.lists[wordListName]
+	if !ok {
+		list = gen.corpus.lists[DefaultList]
+	}
+	return list.filter(gen.KeyFilter)
+}
+
 func (gen *WordGenerator) generateWithPunctuation(words []string) []rune {
 	var result []rune
 	sentenceStart := true
 
 	for i, word := range words {
-		currentWord := word
+		currentWord := gen.punctuateWord(word)
 		if sentenceStart {
-			currentWord = capitalizeFirst(word)
+			currentWord = capitalizeFirst(currentWord)
 			sentenceStart = false
 		}
 
@@ -97,33 +174,55 @@ func (gen *WordGenerator) getNextPunctuation(currentIdx, totalWords int) []rune
 		return []rune{'.'}
 	}
 
-	roll := rand.Float64()
+	profile := gen.profile()
+	roll := gen.random().Float64()
+	var cumulative float64
+	below := func(weight float64) bool {
+		cumulative += weight
+		return roll < cumulative
+	}
 
+	// Each case claims its own slice of the roll, so a separator that doesn't
+	// fit the remaining words falls back to a space instead of shifting the
+	// odds of the ones after it.
 	switch {
-	case roll < 0.15 && remaining > 5:
-		return gen.generateParenthesis()
-	case roll < 0.25 && remaining > 3:
-		return gen.generateQuotedPhrase()
-	case roll < 0.35:
+	case below(profile.Parenthesis):
+		if remaining > 5 {
+			return gen.generateParenthesis()
+		}
+	case below(profile.Quote):
+		if remaining > 3 {
+			return gen.generateQuotedPhrase(profile.NestedQuote)
+		}
+	case below(profile.Semicolon):
 		return []rune{';', ' '}
-	case roll < 0.45:
+	case below(profile.Colon):
+		return []rune{':', ' '}
+	case below(profile.Dash):
+		return []rune{' ', '-', ' '}
+	case below(profile.Comma):
 		return []rune{',', ' '}
-	case roll < 0.55 && remaining > 1:
-		return []rune{'.', ' '}
-	case roll < 0.65 && remaining > 1:
-		return []rune{'!', ' '}
-	case roll < 0.75 && remaining > 1:
-		return []rune{'?', ' '}
-	default:
-		return []rune{' '}
+	case below(profile.Period):
+		if remaining > 1 {
+			return []rune{'.', ' '}
+		}
+	case below(profile.Exclamation):
+		if remaining > 1 {
+			return []rune{'!', ' '}
+		}
+	case below(profile.Question):
+		if remaining > 1 {
+			return []rune{'?', ' '}
+		}
 	}
+	return []rune{' '}
 }
 
 func (gen *WordGenerator) generateParenthesis() []rune {
 	var words []rune
 	words = append(words, ' ')
 	words = append(words, '(')
-	wordCount := rand.IntN(3) + 1
+	wordCount := gen.random().IntN(3) + 1
 	for i := 0; i < wordCount; i++ {
 		if i > 0 {
 			words = append(words, ' ')
@@ -135,26 +234,43 @@ func (gen *WordGenerator) generateParenthesis() []rune {
 	return words
 }
 
-func (gen *WordGenerator) generateQuotedPhrase() []rune {
+// generateQuotedPhrase returns a short quote. With the nested chance, part of
+// a quote of two words or more is quoted again in single quotes.
+func (gen *WordGenerator) generateQuotedPhrase(nested float64) []rune {
 	var words []rune
 	words = append(words, ' ')
 	words = append(words, '"')
-	wordCount := rand.IntN(4) + 1
+	wordCount := gen.random().IntN(4) + 1
+
+	innerStart, innerEnd := -1, -1
+	if wordCount > 1 && gen.random().Float64() < nested {
+		innerStart = gen.random().IntN(wordCount - 1)
+		innerEnd = innerStart + gen.random().IntN(wordCount-innerStart-1) + 1
+	}
+
 	for i := 0; i < wordCount; i++ {
 		if i > 0 {
 			words = append(words, ' ')
 		}
+		if i == innerStart {
+			words = append(words, '\'')
+		}
 		words = append(words, []rune(gen.randomWord())...)
+		if i == innerEnd {
+			words = append(words, '\'')
+		}
 	}
 	words = append(words, '"')
 	words = append(words, ' ')
 	return words
 }
-
 func (gen *WordGenerator) randomWord() string {
+	if gen.weighted != nil {
+		return gen.weighted.pick(gen.random())
+	}
 	if gen.poolIndex >= len(gen.currentPool) {
 		// Reset if we've used all words
-		rand.Shuffle(len(gen.currentPool), func(i, j int) { gen.currentPool[i], gen.currentPool[j] = gen.currentPool[j], gen.currentPool[i] })
+		gen.random().Shuffle(len(gen.currentPool), func(i, j int) { gen.currentPool[i], gen.currentPool[j] = gen.currentPool[j], gen.currentPool[i] })
 		gen.poolIndex = 0
 	}
 	word := gen.currentPool[gen.poolIndex]
@@ -179,15 +295,40 @@ func capitalizeFirst(s string) string {
 	return string(unicode.ToUpper(r)) + s[size:]
 }
 
-func addEmbededSources(sources map[string]WordList) map[string]WordList {
-	var wordList WordList
-	err := json.Unmarshal([]byte(commonWords), &wordList)
+// LanguageList returns the built-in list for a language, or the English
+// list when the language is unknown.
+func LanguageList(language string) string {
+	for _, lang := range Languages {
+		if lang.Name == language {
+			return lang.List
+		}
+	}
+	return DefaultList
+}
 
-	if err != nil {
-		panic(err)
+// ResolveList picks the list a test should draw from. An explicitly chosen
+// list wins; otherwise the language's own list is used.
+func ResolveList(wordList, language string) string {
+	if wordList == "" || wordList == DefaultList {
+		return LanguageList(language)
 	}
+	return wordList
+}
+
+func addEmbededSources(sources map[string]WordList) map[string]WordList {
+	for _, lang := range Languages {
+		data, err := embededLists.ReadFile("embeds/" + lang.file)
+		if err != nil {
+			panic(err)
+		}
 
-	sources["Common words"] = wordList
+		var wordList WordList
+		if err := json.Unmarshal(data, &wordList); err != nil {
+			panic(err)
+		}
+
+		sources[lang.List] = wordList
+	}
 
 	return sources
 }
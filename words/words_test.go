@@ -1,8 +1,13 @@
 package words
 
 import (
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"unicode"
 )
 
 func TestGenerateWithPunctuation(t *testing.T) {
@@ -116,6 +121,416 @@ func TestCapitalizationAfterEndPunct(t *testing.T) {
 	}
 }
 
+func TestPunctuationProfileDistribution(t *testing.T) {
+	const draws = 20000
+	for _, name := range []string{"light", "normal", "heavy"} {
+		profile := PunctuationProfiles[name]
+		gen := NewGenerator()
+		gen.Punctuation = true
+		gen.PunctuationProfile = profile
+		gen.Seed(3)
+		gen.Generate(DefaultList)
+
+		counts := make(map[string]int)
+		for i := 0; i < draws; i++ {
+			separator := string(gen.getNextPunctuation(0, math.MaxInt32))
+			switch {
+			case strings.HasPrefix(separator, " ("):
+				counts["parenthesis"]++
+			case strings.HasPrefix(separator, " \""):
+				counts["quote"]++
+			case separator == " - ":
+				counts["dash"]++
+			case separator == " ":
+				counts["space"]++
+			default:
+				counts[separator]++
+			}
+		}
+
+		expected := map[string]float64{
+			", ":          profile.Comma,
+			". ":          profile.Period,
+			"? ":          profile.Question,
+			"! ":          profile.Exclamation,
+			"; ":          profile.Semicolon,
+			": ":          profile.Colon,
+			"dash":        profile.Dash,
+			"parenthesis": profile.Parenthesis,
+			"quote":       profile.Quote,
+			"space":       1 - profile.separatorTotal(),
+		}
+		for separator, want := range expected {
+			got := float64(counts[separator]) / draws
+			if math.Abs(got-want) > 0.01 {
+				t.Errorf("%s: expected %q about %.3f of the time, got %.3f", name, separator, want, got)
+			}
+		}
+	}
+}
+
+func TestSentenceStartKeepsWordConstructs(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile PunctuationProfile
+		mark    string
+	}{
+		{name: "contraction", profile: PunctuationProfile{Period: 1, Contraction: 1}, mark: "'"},
+		{name: "hyphenated", profile: PunctuationProfile{Period: 1, Hyphenated: 1}, mark: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGenerator()
+			gen.Count = 10
+			gen.Punctuation = true
+			gen.PunctuationProfile = tt.profile
+			gen.Seed(3)
+
+			// Nearly every word ends a sentence, so nearly every word also
+			// starts one.
+			sentenceStart := true
+			for _, word := range strings.Fields(string(gen.Generate(DefaultList))) {
+				if sentenceStart {
+					if !strings.Contains(word, tt.mark) {
+						t.Errorf("expected a sentence-initial word with %q, got %q", tt.mark, word)
+					}
+					if first := []rune(word)[0]; !unicode.IsUpper(first) {
+						t.Errorf("expected %q to be capitalized", word)
+					}
+				}
+				sentenceStart = strings.HasSuffix(word, ".")
+			}
+		})
+	}
+}
+
+func TestPunctuationWordConstructs(t *testing.T) {
+	const draws = 20000
+	profile := PunctuationProfiles["heavy"]
+
+	gen := NewGenerator()
+	gen.Punctuation = true
+	gen.PunctuationProfile = profile
+	gen.Seed(5)
+	gen.Generate(DefaultList)
+
+	contracted, hyphenated := 0, 0
+	for i := 0; i < draws; i++ {
+		word := gen.punctuateWord("word")
+		switch {
+		case strings.Contains(word, "'"):
+			contracted++
+		case strings.HasPrefix(word, "word-"):
+			hyphenated++
+		}
+	}
+	if got := float64(contracted) / draws; math.Abs(got-profile.Contraction) > 0.01 {
+		t.Errorf("expected contractions about %.3f of the time, got %.3f", profile.Contraction, got)
+	}
+	if got := float64(hyphenated) / draws; math.Abs(got-profile.Hyphenated) > 0.01 {
+		t.Errorf("expected hyphenated words about %.3f of the time, got %.3f", profile.Hyphenated, got)
+	}
+
+	nested := 0
+	for i := 0; i < draws/10; i++ {
+		quote := string(gen.generateQuotedPhrase(1))
+		if strings.Count(quote, "'") == 2 {
+			nested++
+		} else if strings.Count(strings.TrimSpace(quote), " ") > 0 {
+			t.Fatalf("a quote of several words should always nest with a chance of 1: %q", quote)
+		}
+	}
+	if nested == 0 {
+		t.Error("expected nested quotes")
+	}
+
+	gen.Generate(LanguageList("German"))
+	for i := 0; i < 1000; i++ {
+		if word := gen.punctuateWord("wort"); strings.Contains(word, "'") {
+			t.Fatalf("English contractions should not show up in German text: %q", word)
+		}
+	}
+}
+
+func TestCustomPunctuationProfile(t *testing.T) {
+	profile, err := ParsePunctuationProfile(CustomPunctuation, map[string]float64{"comma": 0.5, "dash": 0.25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Comma != 0.5 || profile.Dash != 0.25 || profile.Period != 0 {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+	if weights := profile.Weights(); len(weights) != len(PunctuationWeightNames) || weights["dash"] != 0.25 {
+		t.Errorf("unexpected weights: %v", weights)
+	}
+
+	invalid := []map[string]float64{
+		{"comma": 0.6, "period": 0.6},
+		{"comma": -0.1},
+		{"hyphenated": 1.5},
+		{"ellipsis": 0.1},
+	}
+	for _, weights := range invalid {
+		if _, err := ParsePunctuationProfile(CustomPunctuation, weights); err == nil {
+			t.Errorf("expected %v to be rejected", weights)
+		}
+	}
+
+	if profile, _ := ParsePunctuationProfile("unknown", nil); profile != PunctuationProfiles["normal"] {
+		t.Errorf("unknown profiles should fall back to normal, got %+v", profile)
+	}
+}
+
+func TestNumbersAndSymbolsDrill(t *testing.T) {
+	isDigit := func(r rune) bool { return r >= '0' && r <= '9' }
+	isSymbol := func(r rune) bool { return strings.ContainsRune("()[]{}<>_=!&|+-*/%^$@#;:", r) }
+
+	for _, punctuation := range []bool{false, true} {
+		plain := NewGenerator()
+		plain.Count = 200
+		plain.Punctuation = punctuation
+		text := string(plain.Generate(DefaultList))
+		if strings.IndexFunc(text, isDigit) >= 0 {
+			t.Errorf("punctuation=%t: expected no digits without the numbers drill: %q", punctuation, text)
+		}
+
+		numbers := NewGenerator()
+		numbers.Count = 200
+		numbers.Punctuation = punctuation
+		numbers.Numbers = true
+		text = string(numbers.Generate(DefaultList))
+		if strings.IndexFunc(text, isDigit) < 0 {
+			t.Errorf("punctuation=%t: expected digit groups: %q", punctuation, text)
+		}
+
+		symbols := NewGenerator()
+		symbols.Count = 200
+		symbols.Symbols = true
+		text = string(symbols.Generate(DefaultList))
+		if strings.IndexFunc(text, isSymbol) < 0 {
+			t.Errorf("expected programming symbols: %q", text)
+		}
+		if strings.IndexFunc(text, isDigit) >= 0 {
+			t.Errorf("symbols alone should not add digit groups: %q", text)
+		}
+	}
+
+	gen := NewGenerator()
+	gen.Numbers = true
+	gen.Seed(11)
+	kinds := make(map[string]bool)
+	for i := 0; i < 500; i++ {
+		group := gen.numberGroup()
+		switch {
+		case strings.HasPrefix(group, "$"):
+			kinds["price"] = true
+		case strings.HasSuffix(group, "%"):
+			kinds["percent"] = true
+		case strings.Count(group, "-") == 2:
+			kinds["phone"] = true
+		case len(group) == 4 && (strings.HasPrefix(group, "19") || strings.HasPrefix(group, "20")):
+			kinds["year"] = true
+		}
+	}
+	for _, kind := range []string{"price", "percent", "phone", "year"} {
+		if !kinds[kind] {
+			t.Errorf("expected %s digit groups", kind)
+		}
+	}
+
+	stream := NewGenerator()
+	stream.Count = 200
+	stream.Numbers = true
+	stream.Symbols = true
+	if text := string(stream.StartStream(DefaultList)); strings.IndexFunc(text, isDigit) < 0 || strings.IndexFunc(text, isSymbol) < 0 {
+		t.Errorf("expected the stream to mix in numbers and symbols: %q", text)
+	}
+}
+
+func TestKeyFilter(t *testing.T) {
+	for _, name := range []string{"home", "left", "right", "top"} {
+		filter := ParseKeyFilter(name, "")
+		gen := NewGenerator()
+		gen.Count = 100
+		gen.KeyFilter = filter
+
+		for _, word := range strings.Fields(string(gen.Generate(DefaultList))) {
+			if !filter.Allows(word) {
+				t.Errorf("%s: %q uses keys outside %q", name, word, filter.Keys)
+			}
+		}
+		if list := gen.list(DefaultList); len(list.Words) < minFilteredWords {
+			t.Errorf("%s: expected at least %d words, got %d", name, minFilteredWords, len(list.Words))
+		}
+	}
+
+	plain := NewGenerator()
+	if list := plain.list(DefaultList).filter(ParseKeyFilter("left", "")); !list.IsRanked() {
+		t.Error("filtering a ranked list should keep it ranked")
+	}
+
+	custom := ParseKeyFilter(CustomKeyFilter, "QZx1 q")
+	if custom.Keys != "qxz" || custom.String() != "custom:qxz" || custom.Label() != "keys qxz" {
+		t.Errorf("unexpected custom filter: %+v", custom)
+	}
+	gen := NewGenerator()
+	gen.Count = 50
+	gen.Punctuation = true
+	gen.KeyFilter = custom
+	text := string(gen.Generate(DefaultList))
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if !custom.Allows(word) {
+			t.Errorf("%q uses keys outside the custom set", word)
+		}
+	}
+
+	if filter := ParseKeyFilter(CustomKeyFilter, "123"); filter.Active() {
+		t.Errorf("a custom set without letters should filter nothing, got %+v", filter)
+	}
+	if filter := ParseKeyFilter("none", ""); filter.Active() || filter.Label() != "" || filter.String() != "" {
+		t.Errorf("expected no filter, got %+v", filter)
+	}
+}
+
+func TestLoadUserSources(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonList := `{"metadata": {"name": "medical", "size": 3}, "words": ["artery", "femur", "suture"]}`
+	if err := os.WriteFile(filepath.Join(dir, "medical.json"), []byte(jsonList), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api.txt"), []byte("getUser\n\n  listOrders \nDELETE\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.txt"), []byte("two words\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("ignored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := LoadUserSources(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.txt") {
+		t.Errorf("expected an error for broken.txt, got %v", err)
+	}
+
+	gen := NewGenerator()
+	api, ok := gen.corpus.lists["api"]
+	if !ok {
+		t.Fatal("api list should be registered from api.txt")
+	}
+	if strings.Join(api.Words, ",") != "getUser,listOrders,DELETE" {
+		t.Errorf("unexpected api words: %v", api.Words)
+	}
+	if _, ok := gen.corpus.lists["medical"]; !ok {
+		t.Error("medical list should be registered from medical.json")
+	}
+	if _, ok := gen.corpus.lists["broken"]; ok {
+		t.Error("broken list should not be registered")
+	}
+
+	names := ListNames()
+	if names[0] != DefaultList {
+		t.Errorf("expected %q to be listed first, got %v", DefaultList, names)
+	}
+
+	gen.Count = 10
+	for _, word := range strings.Fields(string(gen.Generate("medical"))) {
+		if word != "artery" && word != "femur" && word != "suture" {
+			t.Errorf("unexpected word %q from medical list", word)
+		}
+	}
+}
+
+func TestRegisterSourceRejectsInvalidLists(t *testing.T) {
+	tests := []struct {
+		name string
+		list WordList
+	}{
+		{"no name", WordList{Words: []string{"a"}}},
+		{"no words", WordList{MetaData: MetaData{Name: "empty"}}},
+		{"empty word", WordList{MetaData: MetaData{Name: "gaps"}, Words: []string{"a", ""}}},
+		{"whitespace", WordList{MetaData: MetaData{Name: "spaces"}, Words: []string{"a b"}}},
+		{"built-in name", WordList{MetaData: MetaData{Name: DefaultList}, Words: []string{"a"}}},
+		{"count mismatch", WordList{MetaData: MetaData{Name: "counted", Counts: []int{3}}, Words: []string{"a", "b"}}},
+		{"zero count", WordList{MetaData: MetaData{Name: "zeroes", Counts: []int{3, 0}}, Words: []string{"a", "b"}}},
+	}
+
+	for _, tt := range tests {
+		if err := RegisterSource(tt.list); err == nil {
+			t.Errorf("%s: expected RegisterSource to fail", tt.name)
+		}
+	}
+}
+
+func TestCheckWordList(t *testing.T) {
+	list := WordList{
+		MetaData: MetaData{Name: "messy", Size: 6},
+		Words:    []string{"one", "two", "one", "", "cafe\u0301", "tab\x07"},
+	}
+
+	problems := CheckWordList(list)
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{`repeats "one"`, "empty word at index 3", "U+0301", "U+0007"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected a problem mentioning %q, got:\n%s", want, joined)
+		}
+	}
+
+	// Repeats are reported but don't keep a list from loading.
+	if err := ValidateWordList(WordList{MetaData: MetaData{Name: "repeats", Size: 2}, Words: []string{"a", "a"}}); err != nil {
+		t.Errorf("repeated words should not fail validation: %v", err)
+	}
+	if err := ValidateWordList(WordList{MetaData: MetaData{Name: "sized", Size: 3}, Words: []string{"a", "b"}}); err == nil {
+		t.Error("expected a size that doesn't match the words to fail validation")
+	}
+
+	for _, lang := range Languages {
+		list, ok := FindList(lang.List)
+		if !ok {
+			t.Fatalf("FindList(%q) found nothing", lang.List)
+		}
+		if problems := CheckWordList(list); len(problems) > 0 {
+			t.Errorf("built-in list %s has problems: %v", lang.List, problems)
+		}
+	}
+}
+
+func TestImportWordList(t *testing.T) {
+	text := "alpha beta\n\n  beta gamma\tdelta\nbro\u200bken\n"
+	list, skipped, err := ImportWordList(strings.NewReader(text), "greek", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(list.Words, ",") != "alpha,beta,gamma,delta" {
+		t.Errorf("unexpected words: %v", list.Words)
+	}
+	if skipped != 2 {
+		t.Errorf("expected the repeat and the zero-width space to be skipped, got %d", skipped)
+	}
+	if list.MetaData.Name != "greek" || list.MetaData.Size != 4 || !list.IsRanked() {
+		t.Errorf("unexpected metadata: %+v", list.MetaData)
+	}
+
+	if _, _, err := ImportWordList(strings.NewReader("  \n"), "blank", false); err == nil {
+		t.Error("expected importing a file without words to fail")
+	}
+}
+
+func TestGenerateUnknownListFallsBack(t *testing.T) {
+	gen := NewGenerator()
+	gen.Count = 10
+
+	if len(gen.Generate("does not exist")) == 0 {
+		t.Error("unknown list should fall back to the default list")
+	}
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -129,3 +544,445 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+func TestGenerateQuoteLength(t *testing.T) {
+	gen := NewGenerator()
+	list := gen.corpus.quotes["English quotes"]
+
+	if len(list.Quotes) != list.MetaData.Size {
+		t.Fatalf("expected %d quotes, got %d", list.MetaData.Size, len(list.Quotes))
+	}
+
+	for _, length := range []QuoteLength{QuoteShort, QuoteMedium, QuoteLong} {
+		for i := 0; i < 20; i++ {
+			quote := gen.GenerateQuote("English quotes", length)
+			if quote.Text == "" {
+				t.Fatalf("expected a %s quote, got an empty one", length)
+			}
+			if list.lengthOf(quote) != length {
+				t.Errorf("expected a %s quote, got %q", length, quote.Text)
+			}
+			if quote.Attribution() == "" {
+				t.Errorf("quote %d should carry an attribution", quote.Id)
+			}
+		}
+	}
+}
+
+func TestParseQuoteLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected QuoteLength
+	}{
+		{"short", QuoteShort},
+		{"medium", QuoteMedium},
+		{"long", QuoteLong},
+		{"all", QuoteAll},
+		{"", QuoteAll},
+		{"unknown", QuoteAll},
+	}
+
+	for _, tt := range tests {
+		if got := ParseQuoteLength(tt.name); got != tt.expected {
+			t.Errorf("ParseQuoteLength(%q) = %s, expected %s", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestLanguageListsAreValid(t *testing.T) {
+	gen := NewGenerator()
+
+	for _, lang := range Languages {
+		list, ok := gen.corpus.lists[lang.List]
+		if !ok {
+			t.Errorf("%s list %q is not registered", lang.Name, lang.List)
+			continue
+		}
+		if err := ValidateWordList(list); err != nil {
+			t.Errorf("%s list is invalid: %v", lang.Name, err)
+		}
+		if list.MetaData.Size != len(list.Words) {
+			t.Errorf("%s list declares %d words but has %d", lang.Name, list.MetaData.Size, len(list.Words))
+		}
+
+		for _, word := range list.Words {
+			for _, r := range word {
+				if unicode.Is(unicode.Mn, r) {
+					t.Errorf("%s word %q should use precomposed characters", lang.Name, word)
+				}
+			}
+		}
+	}
+}
+
+func TestResolveList(t *testing.T) {
+	tests := []struct {
+		wordList string
+		language string
+		expected string
+	}{
+		{"", "", DefaultList},
+		{"", "German", "German"},
+		{DefaultList, "Russian", "Russian"},
+		{"medical", "German", "medical"},
+		{"", "Klingon", DefaultList},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveList(tt.wordList, tt.language); got != tt.expected {
+			t.Errorf("ResolveList(%q, %q) = %q, expected %q", tt.wordList, tt.language, got, tt.expected)
+		}
+	}
+}
+
+func TestCapitalizeFirstUnicode(t *testing.T) {
+	tests := map[string]string{
+		"élève": "Élève",
+		"жизнь": "Жизнь",
+		"źle":   "Źle",
+	}
+
+	for input, expected := range tests {
+		if got := capitalizeFirst(input); got != expected {
+			t.Errorf("capitalizeFirst(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestGenerateSnippet(t *testing.T) {
+	gen := NewGenerator()
+
+	languages := CodeLanguages()
+	if len(languages) < 3 {
+		t.Fatalf("expected snippets in several languages, got %v", languages)
+	}
+
+	for _, language := range languages {
+		snippet := gen.GenerateSnippet(language)
+		if snippet.Language != language {
+			t.Errorf("expected a %s snippet, got %s", language, snippet.Language)
+		}
+		if !strings.Contains(snippet.Code, "\n") {
+			t.Errorf("snippet %d should span multiple lines", snippet.Id)
+		}
+		if strings.Contains(snippet.Code, "    ") {
+			t.Errorf("snippet %d should indent with tabs", snippet.Id)
+		}
+		if strings.HasSuffix(snippet.Code, "\n") {
+			t.Errorf("snippet %d should not end with a newline", snippet.Id)
+		}
+	}
+
+	if gen.GenerateSnippet("Cobol").Code == "" {
+		t.Error("an unknown language should fall back to any snippet")
+	}
+}
+
+func TestGenerateFrequencyTiers(t *testing.T) {
+	gen := NewGenerator()
+	gen.Count = 2000
+	list := gen.corpus.lists[DefaultList]
+
+	rank := make(map[string]int, len(list.Words))
+	for i, word := range list.Words {
+		if _, ok := rank[word]; !ok {
+			rank[word] = i
+		}
+	}
+
+	gen.Frequency = FrequencyTop200
+	for _, word := range strings.Fields(string(gen.Generate(DefaultList))) {
+		if rank[word] >= 200 {
+			t.Fatalf("top200 produced %q at rank %d", word, rank[word])
+		}
+	}
+
+	gen.Frequency = FrequencyRare
+	for _, word := range strings.Fields(string(gen.Generate(DefaultList))) {
+		if rank[word] < rareFrom {
+			t.Fatalf("rare produced common word %q at rank %d", word, rank[word])
+		}
+	}
+}
+
+func TestHasTier(t *testing.T) {
+	list := WordList{Words: make([]string, 860)}
+	for _, frequency := range []Frequency{FrequencyAll, FrequencyTop200, FrequencyRare} {
+		if !list.HasTier(frequency) {
+			t.Errorf("expected %s to be offered for 860 words", frequency)
+		}
+	}
+
+	short := WordList{Words: make([]string, 180)}
+	if short.HasTier(FrequencyTop200) {
+		t.Error("expected top200 to be hidden for 180 words")
+	}
+}
+
+func TestGenerateIsFrequencyWeighted(t *testing.T) {
+	gen := NewGenerator()
+	gen.Count = 5000
+	list := gen.corpus.lists[DefaultList]
+
+	counts := make(map[string]int)
+	for _, word := range strings.Fields(string(gen.Generate(DefaultList))) {
+		counts[word]++
+	}
+
+	// Under 1/rank weighting the top word is expected 100 times as often as
+	// the word at rank 100.
+	if counts[list.Words[0]] <= 10*max(1, counts[list.Words[99]]) {
+		t.Errorf("expected %q to dominate %q, got %d vs %d",
+			list.Words[0], list.Words[99], counts[list.Words[0]], counts[list.Words[99]])
+	}
+}
+
+func TestSamplerUsesCounts(t *testing.T) {
+	list := WordList{
+		MetaData: MetaData{Name: "counted", Counts: []int{1, 1000, 1}},
+		Words:    []string{"rare", "common", "odd"},
+	}
+	if !list.IsRanked() {
+		t.Fatal("a list with counts should be ranked")
+	}
+
+	words, _ := list.byFrequency()
+	if words[0] != "common" {
+		t.Errorf("expected counts to reorder the list, got %v", words)
+	}
+
+	seen := make(map[string]int)
+	for _, word := range list.sampler(FrequencyAll).sample(rand.New(rand.NewPCG(1, 1)), 1000) {
+		seen[word]++
+	}
+	if seen["common"] < 900 {
+		t.Errorf("expected the counted word to dominate, got %v", seen)
+	}
+}
+
+func TestUnrankedListsStayUniform(t *testing.T) {
+	list := WordList{MetaData: MetaData{Name: "plain"}, Words: []string{"a", "b", "c"}}
+	if list.IsRanked() {
+		t.Error("a list without rank or counts should not be ranked")
+	}
+
+	if ParseFrequency("top200") != FrequencyTop200 || ParseFrequency("top1k") != FrequencyAll || ParseFrequency("bogus") != FrequencyAll {
+		t.Error("ParseFrequency should map names and fall back to all")
+	}
+}
+
+func TestSeedReproducesText(t *testing.T) {
+	for _, punctuation := range []bool{false, true} {
+		first, second := NewGenerator(), NewGenerator()
+		first.Count, second.Count = 50, 50
+		first.Punctuation, second.Punctuation = punctuation, punctuation
+
+		first.Seed(42)
+		second.Seed(42)
+		a, b := string(first.Generate("German")), string(second.Generate("German"))
+		if a != b {
+			t.Errorf("punctuation=%t: same seed produced different text:\n%s\n%s", punctuation, a, b)
+		}
+
+		first.Seed(42)
+		if again := string(first.Generate("German")); again != a {
+			t.Errorf("punctuation=%t: reseeding should reproduce the text", punctuation)
+		}
+
+		second.Seed(43)
+		if string(second.Generate("German")) == a {
+			t.Errorf("punctuation=%t: different seeds should produce different text", punctuation)
+		}
+	}
+
+	plain := WordList{MetaData: MetaData{Name: "seeded-plain"}, Words: []string{"a", "b", "c", "d", "e", "f"}}
+	if err := RegisterSource(plain); err != nil {
+		t.Fatal(err)
+	}
+	gen := NewGenerator()
+	gen.Count = 6
+	gen.Seed(7)
+	a := string(gen.Generate("seeded-plain"))
+	gen.Seed(7)
+	if b := string(gen.Generate("seeded-plain")); a != b {
+		t.Errorf("unranked lists should reproduce too, got %q and %q", a, b)
+	}
+}
+
+func TestGeneratePractice(t *testing.T) {
+	gen := NewGenerator()
+	gen.Count = 200
+
+	result := strings.Fields(string(gen.GeneratePractice(DefaultList, []string{"qu", "zz"})))
+	if len(result) != 200 {
+		t.Fatalf("expected 200 words, got %d", len(result))
+	}
+	for _, word := range result {
+		if !strings.Contains(word, "qu") && !strings.Contains(word, "zz") {
+			t.Errorf("practice word %q contains none of the bigrams", word)
+		}
+	}
+
+	if len(gen.GeneratePractice(DefaultList, []string{"ǂǂ"})) == 0 {
+		t.Error("bigrams no word contains should fall back to regular text")
+	}
+}
+
+func TestStreamChunksJoinSeamlessly(t *testing.T) {
+	for _, punctuation := range []bool{false, true} {
+		whole := NewGenerator()
+		whole.Count = 120
+		whole.Punctuation = punctuation
+		whole.Seed(9)
+		expected := string(whole.StartStream(DefaultList))
+
+		chunked := NewGenerator()
+		chunked.Count = 40
+		chunked.Punctuation = punctuation
+		chunked.Seed(9)
+		text := string(chunked.StartStream(DefaultList))
+		text += string(chunked.ExtendStream(50))
+		text += string(chunked.ExtendStream(30))
+
+		if text != expected {
+			t.Errorf("punctuation=%t: chunked stream differs from one long stream", punctuation)
+		}
+		if strings.Contains(text, "  ") {
+			t.Errorf("punctuation=%t: chunks should not leave double spaces: %q", punctuation, text)
+		}
+		if !strings.HasSuffix(text, " ") {
+			t.Errorf("punctuation=%t: chunks should end with a separator", punctuation)
+		}
+	}
+}
+
+func TestStreamKeepsSentenceState(t *testing.T) {
+	gen := NewGenerator()
+	gen.Count = 1
+	gen.Punctuation = true
+
+	text := []rune(string(gen.StartStream(DefaultList)))
+	for i := 0; i < 500; i++ {
+		text = append(text, gen.ExtendStream(1)...)
+	}
+
+	if !unicode.IsUpper([]rune(string(text))[0]) {
+		t.Errorf("stream should start with a capital, got %q", string(text[:10]))
+	}
+	for i := 0; i+2 < len(text); i++ {
+		if (text[i] == '.' || text[i] == '!' || text[i] == '?') && text[i+1] == ' ' && unicode.IsLetter(text[i+2]) {
+			if !unicode.IsUpper(text[i+2]) {
+				t.Fatalf("expected a capital after %q at %d, got %q", text[i], i, string(text[i:min(i+20, len(text))]))
+			}
+		}
+	}
+}
+
+func TestLoadBookMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	markdown := "# Chapter One\n\n" +
+		"It was a **bright** cold day in [April](https://example.com), and the\nclocks were striking thirteen.\n\n" +
+		"> “Quoted” text—with dashes…\n\n" +
+		"```\ncode is skipped\n```\n\n" +
+		"- a list item\n\n---\n"
+	if err := os.WriteFile(filepath.Join(dir, "novel.md"), []byte(markdown), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := ListBooks(dir)
+	if err != nil || len(names) != 1 || names[0] != "novel.md" {
+		t.Fatalf("expected only novel.md to be listed, got %v (%v)", names, err)
+	}
+
+	book, err := LoadBook(filepath.Join(dir, "novel.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Chapter One It was a bright cold day in April, and the clocks were striking thirteen. " +
+		"\"Quoted\" text - with dashes... a list item"
+	if text := strings.Join(book.Passages, " "); text != expected {
+		t.Errorf("unexpected book text:\n%q\nexpected:\n%q", text, expected)
+	}
+
+	if books, _ := ListBooks(filepath.Join(dir, "missing")); len(books) != 0 {
+		t.Errorf("a missing directory should have no books, got %v", books)
+	}
+}
+
+func TestSplitPassages(t *testing.T) {
+	sentence := "The quick brown fox jumps over the lazy dog."
+	var paragraphs []string
+	for i := 0; i < 40; i++ {
+		paragraphs = append(paragraphs, sentence)
+	}
+	paragraphs = append(paragraphs, strings.Repeat("endless ", 200))
+
+	passages := splitPassages(paragraphs)
+	if len(passages) < 5 {
+		t.Fatalf("expected the text to be split into several passages, got %d", len(passages))
+	}
+
+	for i, passage := range passages {
+		length := len([]rune(passage))
+		if length > passageMax {
+			t.Errorf("passage %d is %d runes, longer than %d", i, length, passageMax)
+		}
+		if i < 4 && !strings.HasSuffix(passage, ".") {
+			t.Errorf("passage %d should end at a sentence: %q", i, passage)
+		}
+	}
+
+	joined := strings.Join(passages, " ")
+	if joined != strings.Join(strings.Fields(strings.Join(paragraphs, " ")), " ") {
+		t.Error("splitting should not drop or reorder any text")
+	}
+}
+
+func TestGenerateRepeats(t *testing.T) {
+	gen := NewGenerator()
+	gen.Seed(4)
+
+	result := strings.Fields(string(gen.GenerateRepeats([]string{"their", "rhythm", "ancient"}, 3)))
+	if len(result) != 9 {
+		t.Fatalf("expected 9 words, got %v", result)
+	}
+
+	counts := make(map[string]int)
+	for i, word := range result {
+		counts[word]++
+		if i > 0 && result[i-1] == word {
+			t.Errorf("%q repeats back to back in %v", word, result)
+		}
+	}
+	for _, word := range []string{"their", "rhythm", "ancient"} {
+		if counts[word] != 3 {
+			t.Errorf("expected %q 3 times, got %d", word, counts[word])
+		}
+	}
+}
+
+func TestKeyFilterTurnsModifiersOff(t *testing.T) {
+	filter := ParseKeyFilter("home", "")
+	onlyFilterKeys := func(text string) bool {
+		return strings.IndexFunc(text, func(r rune) bool {
+			return !unicode.IsSpace(r) && !strings.ContainsRune(filter.Keys, unicode.ToLower(r)) && !strings.ContainsRune(".,;:!?", r)
+		}) < 0
+	}
+
+	gen := NewGenerator()
+	gen.Count = 200
+	gen.Punctuation = true
+	gen.PunctuationProfile = PunctuationProfile{Period: 1, Hyphenated: 1}
+	gen.Numbers = true
+	gen.Symbols = true
+	gen.KeyFilter = filter
+	if text := string(gen.Generate(DefaultList)); !onlyFilterKeys(text) {
+		t.Errorf("expected only filter keys and sentence punctuation: %q", text)
+	}
+	if text := string(gen.StartStream(DefaultList)); !onlyFilterKeys(text) {
+		t.Errorf("expected the stream to keep to the filter keys too: %q", text)
+	}
+}